@@ -0,0 +1,107 @@
+// Package boot runs real boot rom images, mapped over the cartridge until the
+// boot rom unmaps itself by writing to $FF50.
+package boot
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/wmarshpersonal/gogeebee/cpu"
+	"github.com/wmarshpersonal/gogeebee/model"
+)
+
+// ErrUnknownROM is returned by Parse for images that aren't a known boot rom dump.
+var ErrUnknownROM = errors.New("unknown boot rom")
+
+// knownROMs maps the MD5 of the known boot rom dumps to their model.
+var knownROMs = map[string]model.Model{
+	"a8f84a0ac44da5d3f0ee19f9cea80a8c": model.DMG0,
+	"32fbbd84168d3482956eb3c5051637f5": model.DMG,
+	"71a378e71ff30b2d8a1f02bf5c7896aa": model.MGB,
+	"d574d4f9c12f305074798f54c091a8b4": model.SGB,
+	"e0430bca9925fb9882148fd2dc2418c1": model.SGB2,
+	"dbfce9db9deaa2567f6a84fde55f9680": model.CGB,
+}
+
+// Size returns the size of the model's boot rom: 256 bytes, or 2304 for the
+// CGB & AGB, whose rom is mapped at $0000-$00FF & $0200-$08FF around the
+// cartridge header.
+func Size(m model.Model) int {
+	if m.Color() {
+		return 0x900
+	}
+	return 0x100
+}
+
+// ROM is a boot rom image. Use a ROM literal for images that aren't a known
+// dump, such as modified boot roms.
+type ROM struct {
+	Model model.Model
+	Data  []byte
+}
+
+// Parse validates a boot rom image by its size & hash, identifying its model.
+// Images that aren't a known dump return an error wrapping ErrUnknownROM.
+func Parse(data []byte) (ROM, error) {
+	if len(data) != Size(model.DMG) && len(data) != Size(model.CGB) {
+		return ROM{}, fmt.Errorf("boot rom is %d bytes, want %d or %d", len(data), Size(model.DMG), Size(model.CGB))
+	}
+	sum := md5.Sum(data)
+	m, ok := knownROMs[hex.EncodeToString(sum[:])]
+	if !ok {
+		return ROM{}, fmt.Errorf("%w (md5 %x)", ErrUnknownROM, sum)
+	}
+	return ROM{Model: m, Data: data}, nil
+}
+
+// Mapped reports whether the boot rom is mapped at addr.
+func (r ROM) Mapped(addr uint16) bool {
+	return int(addr) < len(r.Data) && (addr < 0x100 || addr >= 0x200)
+}
+
+// Overlay is a bus with a boot rom mapped over it until a write to $FF50 sets
+// bit 0. Reads from the boot rom clock the bus with a Tick.
+type Overlay struct {
+	cpu.Bus  // the bus the boot rom is mapped over
+	ROM      ROM
+	Disabled bool // the boot rom has been unmapped
+}
+
+// NewOverlay maps rom over bus.
+func NewOverlay(rom ROM, bus cpu.Bus) *Overlay {
+	return &Overlay{Bus: bus, ROM: rom}
+}
+
+func (o *Overlay) Read(addr uint16) uint8 {
+	if !o.Disabled && o.ROM.Mapped(addr) {
+		o.Bus.Tick()
+		return o.ROM.Data[addr]
+	}
+	return o.Bus.Read(addr)
+}
+
+func (o *Overlay) Write(addr uint16, v uint8) {
+	if addr == 0xFF50 && v&1 != 0 {
+		o.Disabled = true
+	}
+	o.Bus.Write(addr, v)
+}
+
+// Interrupts passes on the requests of the bus, if it is an InterruptSource.
+func (o *Overlay) Interrupts() uint8 {
+	if src, ok := o.Bus.(cpu.InterruptSource); ok {
+		return src.Interrupts()
+	}
+	return 0
+}
+
+// NewCore returns a core that runs the boot rom from power on, mapped over bus.
+// Once the boot rom unmaps itself, the core runs on into the cartridge.
+func NewCore(rom ROM, bus cpu.Bus) *cpu.Core {
+	return &cpu.Core{
+		State: *cpu.NewPowerOnState(),
+		Bus:   NewOverlay(rom, bus),
+	}
+}
@@ -0,0 +1,133 @@
+package boot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wmarshpersonal/gogeebee/cartridge"
+	"github.com/wmarshpersonal/gogeebee/cpu"
+	"github.com/wmarshpersonal/gogeebee/model"
+)
+
+// testBus is flat memory with LY counting scanlines, which the boot roms wait on.
+type testBus struct {
+	mem    [0x10000]uint8
+	cycles int
+}
+
+func (b *testBus) Read(addr uint16) uint8 {
+	b.Tick()
+	if addr == 0xFF44 {
+		return uint8(b.cycles / 114 % 154)
+	}
+	return b.mem[addr]
+}
+
+func (b *testBus) Write(addr uint16, v uint8) {
+	b.Tick()
+	b.mem[addr] = v
+}
+
+func (b *testBus) Tick() { b.cycles++ }
+
+// newCartridge returns a bus with a cartridge that passes the boot rom's checks.
+func newCartridge(t *testing.T, program ...uint8) *testBus {
+	var bus testBus
+	copy(bus.mem[0x100:], program)
+	copy(bus.mem[0x104:], cartridge.NintendoLogo[:])
+	copy(bus.mem[0x134:], "GOGEEBEE")
+	bus.mem[0x143] = 0x80 // CGB compatible, for the CGB registers
+	checksum, err := cartridge.ComputeHeaderChecksum(cartridge.Cartridge(bus.mem[:0x8000]))
+	require.NoError(t, err)
+	bus.mem[0x14D] = uint8(checksum)
+	return &bus
+}
+
+func TestParse(t *testing.T) {
+	_, err := Parse(make([]byte, 0x200))
+	assert.EqualError(t, err, "boot rom is 512 bytes, want 256 or 2304")
+
+	_, err = Parse(make([]byte, 0x100))
+	assert.True(t, errors.Is(err, ErrUnknownROM))
+	assert.EqualError(t, err, "unknown boot rom (md5 348a9791dc41b89796ec3808b5b5262f)")
+}
+
+func TestROM_Mapped(t *testing.T) {
+	dmg := ROM{Model: model.DMG, Data: make([]byte, Size(model.DMG))}
+	assert.True(t, dmg.Mapped(0x0000))
+	assert.True(t, dmg.Mapped(0x00FF))
+	assert.False(t, dmg.Mapped(0x0100))
+
+	cgb := ROM{Model: model.CGB, Data: make([]byte, Size(model.CGB))}
+	assert.True(t, cgb.Mapped(0x00FF))
+	assert.False(t, cgb.Mapped(0x0100))
+	assert.False(t, cgb.Mapped(0x01FF))
+	assert.True(t, cgb.Mapped(0x0200))
+	assert.True(t, cgb.Mapped(0x08FF))
+	assert.False(t, cgb.Mapped(0x0900))
+}
+
+func TestNewCore(t *testing.T) {
+	rom := ROM{Model: model.DMG, Data: make([]byte, Size(model.DMG))}
+	copy(rom.Data[0x00:], []uint8{0xC3, 0xFC, 0x00}) // jp $00FC
+	copy(rom.Data[0xFC:], []uint8{
+		0x3E, 0x01, // ld a, $01
+		0xE0, 0x50, // ldh ($50), a
+	})
+	bus := newCartridge(t,
+		0x3C, // inc a
+	)
+	bus.mem[0x0000] = 0x76 // halt, hidden by the boot rom
+
+	c := NewCore(rom, bus)
+	_, err := c.RunUntil(0x0100, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0x0101), c.PC)
+	assert.True(t, c.Bus.(*Overlay).Disabled)
+	assert.Equal(t, 1+4+2+3, bus.cycles, "bus is clocked every cycle")
+
+	_, err = c.StepInstruction()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, c.A)
+	assert.EqualValues(t, 0x76, c.Bus.Read(0x0000), "cartridge is mapped")
+}
+
+// TestBootROMs runs the boot roms found in the directory named by BOOT_ROMS,
+// checking they leave the CPU registers as NewResetStateFor has them.
+func TestBootROMs(t *testing.T) {
+	dir := os.Getenv("BOOT_ROMS")
+	if dir == "" {
+		t.Skip("BOOT_ROMS not set")
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.bin"))
+	require.NoError(t, err)
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+			rom, err := Parse(data)
+			if errors.Is(err, ErrUnknownROM) {
+				t.Skip(err)
+			}
+			require.NoError(t, err)
+
+			c := NewCore(rom, newCartridge(t))
+			want := cpu.NewResetStateFor(rom.Model)
+			for range 10_000_000 {
+				if c.PC == want.PC && c.IR == want.IR && c.S == want.S {
+					// at the write to $FF50
+					got := cpu.State{A: c.A, F: c.F, B: c.B, C: c.C, D: c.D, E: c.E, H: c.H, L: c.L, SP: c.SP}
+					assert.Equal(t, cpu.State{A: want.A, F: want.F, B: want.B, C: want.C, D: want.D, E: want.E, H: want.H, L: want.L, SP: want.SP}, got)
+					return
+				}
+				require.NoError(t, c.Step())
+			}
+			t.Fatalf("%v boot rom didn't finish, at PC $%04X", rom.Model, c.PC-1)
+		})
+	}
+}
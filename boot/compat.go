@@ -0,0 +1,293 @@
+package boot
+
+import "github.com/wmarshpersonal/gogeebee/cartridge"
+
+// CompatPalette is the colorization the CGB boot rom applies to a cartridge
+// without CGB support, as RGB555 colors for BGP, OBP0 & OBP1.
+type CompatPalette struct {
+	BG, OBJ0, OBJ1 [4]uint16
+}
+
+// DefaultCompatPalette is the palette for cartridges the CGB boot rom doesn't
+// recognize: green & blue backgrounds with red objects.
+var DefaultCompatPalette = CompatPalette{
+	BG:   [4]uint16{0x7FFF, 0x1BEF, 0x6180, 0x0000},
+	OBJ0: [4]uint16{0x7FFF, 0x421F, 0x1CF2, 0x0000},
+	OBJ1: [4]uint16{0x7FFF, 0x421F, 0x1CF2, 0x0000},
+}
+
+// CompatTitle is a title the CGB boot rom recognizes, by the sum of its title
+// bytes. Titles sharing a sum are told apart by their 4th letter, Fourth; a
+// Fourth of 0 matches any letter.
+type CompatTitle struct {
+	Checksum uint8
+	Fourth   byte
+	Palette  CompatPalette
+}
+
+// CompatTable is a table of recognized titles, searched in order.
+type CompatTable []CompatTitle
+
+// CompatTitles is the CGB boot rom's table of recognized titles, which
+// CompatPaletteFor searches.
+var CompatTitles = compatTitles()
+
+// TitleChecksum returns the sum of the title bytes at $0134-$0143, which the
+// CGB boot rom looks titles up by.
+func TitleChecksum(h cartridge.Header) uint8 {
+	var sum uint8
+	for _, b := range h.Title {
+		sum += b
+	}
+	return sum
+}
+
+// Lookup returns the palette the CGB boot rom picks for a cartridge without
+// CGB support: only cartridges published by Nintendo are looked up by title,
+// and those not found in the table get DefaultCompatPalette.
+func (t CompatTable) Lookup(h cartridge.Header) CompatPalette {
+	if !h.Nintendo() {
+		return DefaultCompatPalette
+	}
+	sum := TitleChecksum(h)
+	for _, title := range t {
+		if title.Checksum == sum && (title.Fourth == 0 || title.Fourth == h.Title[3]) {
+			return title.Palette
+		}
+	}
+	return DefaultCompatPalette
+}
+
+// CompatPaletteFor returns the palette the CGB boot rom picks for a cartridge
+// without CGB support, from its header, by looking it up in CompatTitles.
+func CompatPaletteFor(h cartridge.Header) CompatPalette {
+	return CompatTitles.Lookup(h)
+}
+
+// The tables of the CGB boot rom. A title checksum is looked up in
+// compatChecksums, and its index selects a combination of palettes through
+// compatCombos. Checksums from index firstDuplicate on are shared by several
+// titles, and must also match the 4th letter of the title in compatFourths.
+const firstDuplicate = 0x41
+
+var compatChecksums = [...]uint8{
+	0x00, // default
+	0x88, // ALLEY WAY
+	0x16, // YAKUMAN
+	0x36, // BASEBALL, (Game and Watch 2)
+	0xD1, // TENNIS
+	0xDB, // TETRIS
+	0xF2, // QIX
+	0x3C, // DR.MARIO
+	0x8C, // RADARMISSION
+	0x92, // F1RACE
+	0x3D, // YOSSY NO TAMAGO
+	0x5C,
+	0x58, // X
+	0xC9, // MARIOLAND2
+	0x3E, // YOSSY NO COOKIE
+	0x70, // ZELDA
+	0x1D,
+	0x59,
+	0x69, // TETRIS FLASH
+	0x19, // DONKEY KONG
+	0x35, // MARIO'S PICROSS
+	0xA8,
+	0x14, // POKEMON RED, (GAMEBOYCAMERA G)
+	0xAA, // POKEMON GREEN
+	0x75, // PICROSS 2
+	0x95, // YOSSY NO PANEPON
+	0x99, // KIRAKIRA KIDS
+	0x34, // GAMEBOY GALLERY
+	0x6F, // POCKETCAMERA
+	0x15,
+	0xFF, // BALLOON KID
+	0x97, // KINGOFTHEZOO
+	0x4B, // DMG FOOTBALL
+	0x90, // WORLD CUP
+	0x17, // OTHELLO
+	0x10, // SUPER RC PRO-AM
+	0x39, // DYNABLASTER
+	0xF7, // BOY AND BLOB GB2
+	0xF6, // MEGAMAN
+	0xA2, // STAR WARS-NOA
+	0x49,
+	0x4E, // WAVERACE
+	0x43,
+	0x68, // LOLO2
+	0xE0, // YOSHI'S COOKIE
+	0x8B, // MYSTIC QUEST
+	0xF0,
+	0xCE, // TOPRANKINGTENNIS
+	0x0C, // MANSELL
+	0x29, // MEGAMAN3
+	0xE8, // SPACE INVADERS
+	0xB7, // GAME&WATCH
+	0x86, // DONKEYKONGLAND95
+	0x9A, // ASTEROIDS/MISCMD
+	0x52, // STREET FIGHTER 2
+	0x01, // DEFENDER/JOUST
+	0x9D, // KILLERINSTINCT95
+	0x71, // TETRIS BLAST
+	0x9C, // PINOCCHIO
+	0xBD,
+	0x5D, // BA.TOSHINDEN
+	0x6D, // NETTOU KOF 95
+	0x67,
+	0x3F, // TETRIS PLUS
+	0x6B, // DONKEYKONGLAND 3
+	// firstDuplicate
+	0xB3,
+	0x46,
+	0x28, // GOLF
+	0xA5, // SOLARSTRIKER
+	0xC6, // GBWARS
+	0xD3, // KAERUNOTAMENI
+	0x27,
+	0x61, // POKEMON BLUE
+	0x18, // DONKEYKONGLAND
+	0x66, // GAMEBOY GALLERY2
+	0x6A, // DONKEYKONGLAND 2
+	0xBF, // KID ICARUS
+	0x0D, // TETRIS2
+	0xF4,
+	0xB3, // MOGURANYA
+	0x46,
+	0x28, // GALAGA&GALAXIAN, with a trailing space
+	0xA5, // BT2RAGNAROKWORLD
+	0xC6, // KEN GRIFFEY JR
+	0xD3,
+	0x27, // MAGNETIC SOCCER
+	0x61, // VEGAS STAKES
+	0x18,
+	0x66, // MILLI/CENTI/PEDE
+	0x6A, // MARIO & YOSHI
+	0xBF, // SOCCER
+	0x0D, // POKEBOM
+	0xF4, // G&W GALLERY
+	0xB3, // TETRIS ATTACK
+}
+
+// compatFourths are the 4th letters of the titles from firstDuplicate on.
+const compatFourths = "BEFAARBEKEK R-URAR INAILICE R"
+
+// compatComboIndices select the entry of compatCombos for each checksum.
+var compatComboIndices = [len(compatChecksums)]uint8{
+	0, 4, 5, 35, 34, 3, 31, 15, 10, 5, 19, 36, 7, 37, 30, 44,
+	21, 32, 31, 20, 5, 33, 13, 14, 5, 29, 5, 18, 9, 3, 2, 26,
+	25, 25, 41, 42, 26, 45, 42, 45, 36, 38, 26, 42, 30, 41, 34, 34,
+	5, 42, 6, 5, 33, 25, 42, 42, 40, 2, 16, 25, 42, 42, 5, 0,
+	39, 36, 22, 25, 6, 32, 12, 36, 11, 39, 18, 39, 24, 31, 50, 17,
+	46, 6, 27, 0, 47, 41, 41, 0, 0, 19, 34, 23, 18, 29,
+}
+
+// compatCombos are the combinations of palettes, as offsets in colors into
+// compatColors for OBJ0, OBJ1 & BG. A few aren't aligned to a palette, and so
+// take colors from two neighbouring palettes.
+var compatCombos = [...][3]int{
+	{4 * 4, 4 * 4, 29 * 4},
+	{18 * 4, 18 * 4, 18 * 4},
+	{20 * 4, 20 * 4, 20 * 4},
+	{24 * 4, 24 * 4, 24 * 4},
+	{9 * 4, 9 * 4, 9 * 4},
+	{0 * 4, 0 * 4, 0 * 4},
+	{27 * 4, 27 * 4, 27 * 4},
+	{5 * 4, 5 * 4, 5 * 4},
+	{12 * 4, 12 * 4, 12 * 4},
+	{26 * 4, 26 * 4, 26 * 4},
+	{16 * 4, 8 * 4, 8 * 4},
+	{4 * 4, 28 * 4, 28 * 4},
+	{4 * 4, 2 * 4, 2 * 4},
+	{3 * 4, 4 * 4, 4 * 4},
+	{4 * 4, 29 * 4, 29 * 4},
+	{28 * 4, 4 * 4, 28 * 4},
+	{2 * 4, 17 * 4, 2 * 4},
+	{16 * 4, 16 * 4, 8 * 4},
+	{4 * 4, 4 * 4, 7 * 4},
+	{4 * 4, 4 * 4, 18 * 4},
+	{4 * 4, 4 * 4, 20 * 4},
+	{19 * 4, 19 * 4, 9 * 4},
+	{4*4 - 1, 4*4 - 1, 11 * 4},
+	{17 * 4, 17 * 4, 2 * 4},
+	{4 * 4, 4 * 4, 2 * 4},
+	{4 * 4, 4 * 4, 3 * 4},
+	{28 * 4, 28 * 4, 0 * 4},
+	{3 * 4, 3 * 4, 0 * 4},
+	{0 * 4, 0 * 4, 1 * 4},
+	{18 * 4, 22 * 4, 18 * 4},
+	{20 * 4, 22 * 4, 20 * 4},
+	{24 * 4, 22 * 4, 24 * 4},
+	{16 * 4, 22 * 4, 8 * 4},
+	{17 * 4, 4 * 4, 13 * 4},
+	{28*4 - 1, 0 * 4, 14 * 4},
+	{28*4 - 1, 4 * 4, 15 * 4},
+	{19 * 4, 22 * 4, 9 * 4},
+	{16 * 4, 28 * 4, 10 * 4},
+	{4 * 4, 23 * 4, 28 * 4},
+	{17 * 4, 22 * 4, 2 * 4},
+	{4 * 4, 0 * 4, 2 * 4},
+	{4 * 4, 28 * 4, 3 * 4},
+	{28 * 4, 3 * 4, 0 * 4},
+	{3 * 4, 28 * 4, 4 * 4},
+	{21 * 4, 28 * 4, 4 * 4},
+	{3 * 4, 28 * 4, 0 * 4},
+	{25 * 4, 3 * 4, 28 * 4},
+	{0 * 4, 28 * 4, 8 * 4},
+	{4 * 4, 3 * 4, 28 * 4},
+	{28 * 4, 3 * 4, 6 * 4},
+	{4 * 4, 28 * 4, 29 * 4},
+}
+
+// compatColors are the boot rom's palettes, 4 RGB555 colors each.
+var compatColors = [...]uint16{
+	0x7FFF, 0x32BF, 0x00D0, 0x0000,
+	0x639F, 0x4279, 0x15B0, 0x04CB,
+	0x7FFF, 0x6E31, 0x454A, 0x0000,
+	0x7FFF, 0x1BEF, 0x0200, 0x0000,
+	0x7FFF, 0x421F, 0x1CF2, 0x0000,
+	0x7FFF, 0x5294, 0x294A, 0x0000,
+	0x7FFF, 0x03FF, 0x012F, 0x0000,
+	0x7FFF, 0x03EF, 0x01D6, 0x0000,
+	0x7FFF, 0x42B5, 0x3DC8, 0x0000,
+	0x7E74, 0x03FF, 0x0180, 0x0000,
+	0x67FF, 0x77AC, 0x1A13, 0x2D6B,
+	0x7ED6, 0x4BFF, 0x2175, 0x0000,
+	0x53FF, 0x4A5F, 0x7E52, 0x0000,
+	0x4FFF, 0x7ED2, 0x3A4C, 0x1CE0,
+	0x03ED, 0x7FFF, 0x255F, 0x0000,
+	0x036A, 0x021F, 0x03FF, 0x7FFF,
+	0x7FFF, 0x01DF, 0x0112, 0x0000,
+	0x231F, 0x035F, 0x00F2, 0x0009,
+	0x7FFF, 0x03EA, 0x011F, 0x0000,
+	0x299F, 0x001A, 0x000C, 0x0000,
+	0x7FFF, 0x027F, 0x001F, 0x0000,
+	0x7FFF, 0x03E0, 0x0206, 0x0120,
+	0x7FFF, 0x7EEB, 0x001F, 0x7C00,
+	0x7FFF, 0x3FFF, 0x7E00, 0x001F,
+	0x7FFF, 0x03FF, 0x001F, 0x0000,
+	0x03FF, 0x001F, 0x000C, 0x0000,
+	0x7FFF, 0x033F, 0x0193, 0x0000,
+	0x0000, 0x4200, 0x037F, 0x7FFF,
+	0x7FFF, 0x7E8C, 0x7C00, 0x0000,
+	0x7FFF, 0x1BEF, 0x6180, 0x0000,
+}
+
+// compatTitles builds the table of recognized titles from the boot rom's tables.
+func compatTitles() CompatTable {
+	palette := func(offset int) (p [4]uint16) {
+		copy(p[:], compatColors[offset:])
+		return p
+	}
+	t := make(CompatTable, len(compatChecksums))
+	for i, sum := range compatChecksums {
+		combo := compatCombos[compatComboIndices[i]]
+		t[i] = CompatTitle{
+			Checksum: sum,
+			Palette:  CompatPalette{OBJ0: palette(combo[0]), OBJ1: palette(combo[1]), BG: palette(combo[2])},
+		}
+		if i >= firstDuplicate {
+			t[i].Fourth = compatFourths[i-firstDuplicate]
+		}
+	}
+	return t
+}
@@ -0,0 +1,107 @@
+package boot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wmarshpersonal/gogeebee/cartridge"
+)
+
+func TestCompatTable_Lookup(t *testing.T) {
+	var (
+		red   = CompatPalette{BG: [4]uint16{0x7FFF, 0x421F, 0x1CF2, 0x0000}}
+		blue  = CompatPalette{BG: [4]uint16{0x7FFF, 0x7E10, 0x48E7, 0x0000}}
+		green = CompatPalette{BG: [4]uint16{0x7FFF, 0x1BEF, 0x6180, 0x0000}}
+	)
+	header := func(title string, old cartridge.OldLicensee, new string) cartridge.Header {
+		var h cartridge.Header
+		copy(h.Title[:], title)
+		h.OldLicensee = old
+		copy(h.NewLicensee[:], new)
+		return h
+	}
+
+	abcd := header("ABCD", cartridge.Nintendo, "")
+	abce := header("ABCE", cartridge.Nintendo, "")
+	table := CompatTable{
+		{Checksum: TitleChecksum(abcd), Fourth: 'D', Palette: red},
+		{Checksum: TitleChecksum(abcd), Fourth: 'C', Palette: blue},
+		{Checksum: TitleChecksum(abce), Palette: green},
+	}
+	assert.EqualValues(t, ('A'+'B'+'C'+'D')%0x100, TitleChecksum(abcd), "wraps around")
+
+	assert.Equal(t, red, table.Lookup(abcd))
+	assert.Equal(t, blue, table.Lookup(header("ABDC", cartridge.Nintendo, "")), "same sum, told apart by the 4th letter")
+	assert.Equal(t, green, table.Lookup(abce))
+	assert.Equal(t, red, table.Lookup(header("ABCD", cartridge.UseNewLicensee, "01")), "new licensee code")
+	assert.Equal(t, DefaultCompatPalette, table.Lookup(header("ABCD", cartridge.UseNewLicensee, "08")), "other publisher")
+	assert.Equal(t, DefaultCompatPalette, table.Lookup(header("ABCD", 0x08, "")), "other publisher")
+	assert.Equal(t, DefaultCompatPalette, table.Lookup(header("ABCY", cartridge.Nintendo, "")), "4th letter doesn't match")
+	assert.Equal(t, DefaultCompatPalette, table.Lookup(header("ZZZZ", cartridge.Nintendo, "")), "unknown title")
+}
+
+func TestCompatTitles(t *testing.T) {
+	assert.Len(t, compatChecksums, firstDuplicate+len(compatFourths))
+	assert.Equal(t, DefaultCompatPalette, CompatTitles[0].Palette, "index 0 is the default")
+
+	header := func(title string) cartridge.Header {
+		h := cartridge.Header{OldLicensee: cartridge.Nintendo}
+		copy(h.Title[:], title)
+		return h
+	}
+	var (
+		red   = [4]uint16{0x7FFF, 0x421F, 0x1CF2, 0x0000}
+		green = [4]uint16{0x7FFF, 0x1BEF, 0x0200, 0x0000}
+		blue  = [4]uint16{0x7FFF, 0x7E8C, 0x7C00, 0x0000}
+	)
+	for _, tt := range []struct {
+		title    string
+		checksum uint8
+		want     CompatPalette
+	}{
+		{"POKEMON RED", 0x14, CompatPalette{BG: red, OBJ0: green, OBJ1: red}},
+		{"POKEMON BLUE", 0x61, CompatPalette{BG: blue, OBJ0: red, OBJ1: blue}},
+		{"POKEMON GREEN", 0xAA, CompatPalette{BG: DefaultCompatPalette.BG, OBJ0: red, OBJ1: DefaultCompatPalette.BG}},
+		{"TETRIS", 0xDB, CompatPalette{
+			BG:   [4]uint16{0x7FFF, 0x03FF, 0x001F, 0x0000},
+			OBJ0: [4]uint16{0x7FFF, 0x03FF, 0x001F, 0x0000},
+			OBJ1: [4]uint16{0x7FFF, 0x03FF, 0x001F, 0x0000},
+		}},
+		{"ZELDA", 0x70, CompatPalette{
+			BG:   red,
+			OBJ0: [4]uint16{0x7FFF, 0x03E0, 0x0206, 0x0120},
+			OBJ1: blue,
+		}},
+		{"MARIOLAND2", 0xC9, CompatPalette{
+			BG:   [4]uint16{0x67FF, 0x77AC, 0x1A13, 0x2D6B},
+			OBJ0: [4]uint16{0x7FFF, 0x01DF, 0x0112, 0x0000},
+			OBJ1: blue,
+		}},
+		{"MOGURANYA", 0xB3, CompatPalette{
+			BG:   [4]uint16{0x7FFF, 0x42B5, 0x3DC8, 0x0000},
+			OBJ0: [4]uint16{0x7FFF, 0x01DF, 0x0112, 0x0000},
+			OBJ1: [4]uint16{0x7FFF, 0x01DF, 0x0112, 0x0000},
+		}},
+	} {
+		h := header(tt.title)
+		assert.Equalf(t, tt.checksum, TitleChecksum(h), "%s", tt.title)
+		assert.Equalf(t, tt.want, CompatPaletteFor(h), "%s", tt.title)
+	}
+
+	// objects off the palette boundaries, from the last color of green on
+	assert.Equal(t, CompatTitle{
+		Checksum: 0x46,
+		Fourth:   'E',
+		Palette: CompatPalette{
+			BG:   [4]uint16{0x7ED6, 0x4BFF, 0x2175, 0x0000},
+			OBJ0: [4]uint16{0x0000, 0x7FFF, 0x421F, 0x1CF2},
+			OBJ1: [4]uint16{0x0000, 0x7FFF, 0x421F, 0x1CF2},
+		},
+	}, CompatTitles[0x42])
+
+	// a title sharing MOGURANYA's checksum without a known 4th letter
+	h := header("MOGURANYA")
+	h.Title[3], h.Title[4] = 'X', 'R'-'X'+'U'
+	assert.EqualValues(t, 0xB3, TitleChecksum(h))
+	assert.Equal(t, DefaultCompatPalette, CompatPaletteFor(h))
+}
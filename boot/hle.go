@@ -0,0 +1,255 @@
+package boot
+
+import (
+	"github.com/wmarshpersonal/gogeebee/cartridge"
+	"github.com/wmarshpersonal/gogeebee/cpu"
+	"github.com/wmarshpersonal/gogeebee/gb"
+	"github.com/wmarshpersonal/gogeebee/model"
+)
+
+// Hardware is the state a boot rom leaves the hardware in when it starts the
+// cartridge. WRAM & OAM are left as found at power on, which is random on
+// hardware.
+type Hardware struct {
+	Model model.Model
+	CPU   cpu.State
+	Timer gb.Timer
+
+	// IO is the memory-mapped registers at $FF00-$FF7F as they read back,
+	// including the wave RAM at $FF30-$FF3F.
+	IO [0x80]uint8
+
+	// VRAM is bank 0 of video RAM.
+	VRAM [0x2000]uint8
+
+	// HRAM is high RAM at $FF80-$FFFE. Only the bytes the boot rom's stack
+	// leaves are set; the rest is random on hardware.
+	HRAM [0x7F]uint8
+
+	// BGPalettes & OBJPalettes are the CGB's palette RAM, as RGB555 colors.
+	BGPalettes, OBJPalettes [8][4]uint16
+
+	// DMGCompat is set when the CGB runs the cartridge in DMG compatibility mode.
+	DMGCompat bool
+}
+
+// dmgIO is the registers left by the DMG boot rom, as read back.
+var dmgIO = map[uint16]uint8{
+	0xFF00: 0xCF, // P1
+	0xFF01: 0x00, // SB
+	0xFF02: 0x7E, // SC
+	0xFF0F: 0xE1, // IF
+	0xFF10: 0x80, // NR10
+	0xFF11: 0xBF, // NR11
+	0xFF12: 0xF3, // NR12
+	0xFF13: 0xFF, // NR13
+	0xFF14: 0xBF, // NR14
+	0xFF16: 0x3F, // NR21
+	0xFF17: 0x00, // NR22
+	0xFF18: 0xFF, // NR23
+	0xFF19: 0xBF, // NR24
+	0xFF1A: 0x7F, // NR30
+	0xFF1B: 0xFF, // NR31
+	0xFF1C: 0x9F, // NR32
+	0xFF1D: 0xFF, // NR33
+	0xFF1E: 0xBF, // NR34
+	0xFF20: 0xFF, // NR41
+	0xFF21: 0x00, // NR42
+	0xFF22: 0x00, // NR43
+	0xFF23: 0xBF, // NR44
+	0xFF24: 0x77, // NR50
+	0xFF25: 0xF3, // NR51
+	0xFF26: 0xF1, // NR52
+	0xFF40: 0x91, // LCDC
+	0xFF41: 0x85, // STAT
+	0xFF42: 0x00, // SCY
+	0xFF43: 0x00, // SCX
+	0xFF44: 0x00, // LY
+	0xFF45: 0x00, // LYC
+	0xFF46: 0xFF, // DMA
+	0xFF47: 0xFC, // BGP
+	0xFF48: 0xFF, // OBP0, uninitialized
+	0xFF49: 0xFF, // OBP1, uninitialized
+	0xFF4A: 0x00, // WY
+	0xFF4B: 0x00, // WX
+}
+
+// cgbIO is the registers the CGB boot rom leaves differently from the DMG's.
+var cgbIO = map[uint16]uint8{
+	0xFF02: 0x7F, // SC
+	0xFF46: 0x00, // DMA
+	0xFF4D: 0x7E, // KEY1
+	0xFF4F: 0xFE, // VBK
+	0xFF51: 0xFF, // HDMA1
+	0xFF52: 0xFF, // HDMA2
+	0xFF53: 0xFF, // HDMA3
+	0xFF54: 0xFF, // HDMA4
+	0xFF55: 0xFF, // HDMA5
+	0xFF56: 0x3E, // RP
+	0xFF70: 0xF8, // SVBK
+}
+
+// The wave RAM isn't initialized, and powers up differently between units.
+// These are typical contents.
+var (
+	dmgWaveRAM = [16]uint8{0x84, 0x40, 0x43, 0xAA, 0x2D, 0x78, 0x92, 0x3C, 0x60, 0x59, 0x59, 0xB0, 0x34, 0xB8, 0x2E, 0xDA}
+	cgbWaveRAM = [16]uint8{0x00, 0xFF, 0x00, 0xFF, 0x00, 0xFF, 0x00, 0xFF, 0x00, 0xFF, 0x00, 0xFF, 0x00, 0xFF, 0x00, 0xFF}
+)
+
+// registeredTile is the ® drawn after the logo, from the DMG boot rom.
+var registeredTile = [8]uint8{0x3C, 0x42, 0xB9, 0xA5, 0xB9, 0xA5, 0x42, 0x3C}
+
+// HLE returns the state the model's boot rom leaves the hardware in when it
+// starts the cartridge with header h, without running a boot rom.
+//
+// The DMG0, DMG & MGB boot roms leave the logo in VRAM & the tile map, and the
+// DMG & MGB's leave their stack in HRAM. On the CGB & AGB, cartridges without
+// CGB support run in DMG compatibility mode, colorized with CompatPaletteFor(h).
+//
+// What the SGB & CGB boot roms leave in VRAM & HRAM isn't reproduced: it's left
+// clear, unlike on hardware, as are the CGB's VRAM bank 1 & tile attributes.
+// Software depending on it needs a real boot rom.
+func HLE(m model.Model, h cartridge.Header) Hardware {
+	hw := Hardware{
+		Model: m,
+		CPU:   *cpu.NewResetStateFor(m),
+		Timer: gb.TimerFor(m),
+	}
+
+	for addr := range hw.IO {
+		hw.IO[addr] = 0xFF // not present, or unreadable
+	}
+	for addr, v := range dmgIO {
+		hw.IO[addr-0xFF00] = v
+	}
+	for addr, reg := range map[uint16]gb.TimerReg{0xFF04: gb.DIV, 0xFF05: gb.TIMA, 0xFF06: gb.TMA, 0xFF07: gb.TAC} {
+		hw.IO[addr-0xFF00] = hw.Timer.Read(reg)
+	}
+
+	switch {
+	case m == model.DMG0:
+		copy(hw.IO[0x30:], dmgWaveRAM[:])
+		hw.VRAM = logoVRAM(h.Logo)
+
+	case m == model.DMG || m == model.MGB:
+		copy(hw.IO[0x30:], dmgWaveRAM[:])
+		hw.VRAM = logoVRAM(h.Logo)
+		stack := logoStack(h.Logo)
+		copy(hw.HRAM[0xFFFA-0xFF80:], stack[:])
+		if h.Checksum == 0 {
+			hw.CPU.F = cpu.FZ // H & C are left by the header checksum check
+		}
+
+	case m.Super():
+		copy(hw.IO[0x30:], dmgWaveRAM[:])
+		hw.IO[0x00] = 0xFF // the joypad is left selecting neither row after the SNES transfer
+		hw.IO[0x26] = 0xF0 // no sound was played
+
+	case m.Color():
+		for addr, v := range cgbIO {
+			hw.IO[addr-0xFF00] = v
+		}
+		copy(hw.IO[0x30:], cgbWaveRAM[:])
+
+		for i := range hw.BGPalettes {
+			hw.BGPalettes[i] = [4]uint16{0x7FFF, 0x7FFF, 0x7FFF, 0x7FFF}
+		}
+		if cgbFlag := h.Title[15]; cgbFlag&0x80 == 0 {
+			hw.DMGCompat = true
+			p := CompatPaletteFor(h)
+			hw.BGPalettes[0], hw.OBJPalettes[0], hw.OBJPalettes[1] = p.BG, p.OBJ0, p.OBJ1
+
+			// B is left with the title checksum of cartridges published by
+			// Nintendo, and HL pointing into the tile map for two of them
+			hw.CPU.B, hw.CPU.D, hw.CPU.E, hw.CPU.H, hw.CPU.L = 0x00, 0x00, 0x08, 0x00, 0x7C
+			if h.Nintendo() {
+				hw.CPU.B = TitleChecksum(h)
+				if hw.CPU.B == 0x43 || hw.CPU.B == 0x58 {
+					hw.CPU.H, hw.CPU.L = 0x99, 0x1A
+				}
+			}
+			if m == model.AGB {
+				// inc b, which tells the AGB apart
+				hw.CPU.B++
+				hw.CPU.F = 0
+				if hw.CPU.B == 0 {
+					hw.CPU.F |= cpu.FZ
+				}
+				if hw.CPU.B&0xF == 0 {
+					hw.CPU.F |= cpu.FH
+				}
+			}
+		}
+	}
+
+	return hw
+}
+
+// logoStack returns what the DMG boot rom's logo loop leaves on the stack at
+// $FFFA-$FFFD. The loop calls its decoding routine twice per logo byte, at
+// $0095 & $0096, and the routine pushes BC as it shifts the byte's bits out of
+// C, through the carry, into A. The last call leaves its return address, $002E,
+// and its last push of BC.
+func logoStack(logo cartridge.Logo) [4]uint8 {
+	rl := func(v, carry uint8) (uint8, uint8) {
+		return v<<1 | carry, v >> 7
+	}
+
+	var a, b, c, carry uint8 // carry is cleared by xor a at the start of the rom
+	var pushB, pushC uint8
+	for i, v := range logo {
+		a, c = v, v // ld a, (de); ld c, a at $0095
+		for range 2 {
+			for b = 4; b > 0; b-- {
+				pushB, pushC = b, c // push bc
+				_, carry = rl(c, carry)
+				a, carry = rl(a, carry)
+				// pop bc
+				c, carry = rl(c, carry)
+				a, carry = rl(a, carry)
+			}
+		}
+		// inc de; ld a, e; cp $34
+		e := uint8(0x104 + i + 1)
+		carry = 0
+		if e < 0x34 {
+			carry = 1
+		}
+	}
+	return [4]uint8{pushC, pushB, 0x2E, 0x00}
+}
+
+// logoVRAM returns VRAM as the DMG boot rom leaves it: the logo scaled up 2x
+// into tiles 1-24, ® in tile 25, and the tiles placed in the middle of the
+// tile map.
+func logoVRAM(logo cartridge.Logo) [0x2000]uint8 {
+	var vram [0x2000]uint8
+
+	// each nibble of the logo is a row of 4 pixels, doubled in both directions
+	double := func(nibble uint8) (row uint8) {
+		for i := 3; i >= 0; i-- {
+			row = row<<2 | (nibble>>i&1)*0b11
+		}
+		return row
+	}
+	addr := 0x0010
+	for _, b := range logo {
+		for _, row := range []uint8{double(b >> 4), double(b & 0xF)} {
+			vram[addr], vram[addr+2] = row, row
+			addr += 4
+		}
+	}
+	for _, row := range registeredTile {
+		vram[addr] = row
+		addr += 2
+	}
+
+	// tiles 1-12 & 13-24 on rows 8 & 9 from column 4, ® after them on row 8
+	for i := range 12 {
+		vram[0x1904+i] = uint8(1 + i)
+		vram[0x1924+i] = uint8(13 + i)
+	}
+	vram[0x1910] = 0x19
+
+	return vram
+}
@@ -0,0 +1,136 @@
+package boot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wmarshpersonal/gogeebee/cartridge"
+	"github.com/wmarshpersonal/gogeebee/cpu"
+	"github.com/wmarshpersonal/gogeebee/gb"
+	"github.com/wmarshpersonal/gogeebee/model"
+)
+
+func TestHLE(t *testing.T) {
+	h := cartridge.Header{Logo: cartridge.NintendoLogo, Checksum: 0x66}
+
+	t.Run("every model", func(t *testing.T) {
+		h := h
+		h.Title[15] = 0x80 // CGB support, so the CGB's registers are left as reset
+		for _, m := range model.Models {
+			hw := HLE(m, h)
+			assert.Equalf(t, *cpu.NewResetStateFor(m), hw.CPU, "%v", m)
+			assert.Equalf(t, gb.TimerFor(m), hw.Timer, "%v", m)
+			assert.Equalf(t, hw.Timer.Read(gb.DIV), hw.IO[0x04], "%v: DIV", m)
+			assert.EqualValuesf(t, 0x91, hw.IO[0x40], "%v: LCDC", m)
+			assert.EqualValuesf(t, 0xFF, hw.IO[0x50], "%v: boot rom disable", m)
+		}
+	})
+
+	t.Run("DMG logo", func(t *testing.T) {
+		hw := HLE(model.DMG, h)
+
+		// $CE: rows of $C & $E, doubled
+		assert.Equal(t, []uint8{0xF0, 0x00, 0xF0, 0x00, 0xFC, 0x00, 0xFC, 0x00}, hw.VRAM[0x10:0x18])
+		// ®
+		assert.Equal(t, []uint8{0x3C, 0x00, 0x42, 0x00}, hw.VRAM[0x190:0x194])
+		// tile map
+		assert.Equal(t, []uint8{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 0x19, 0}, hw.VRAM[0x1903:0x1912])
+		assert.Equal(t, []uint8{0, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 0}, hw.VRAM[0x1923:0x1931])
+
+		assert.Equal(t, uint8(0xB0), hw.CPU.F)
+		h := h
+		h.Checksum = 0
+		assert.Equal(t, cpu.FZ, HLE(model.DMG, h).CPU.F, "H & C are clear for a checksum of 0")
+	})
+
+	t.Run("DMG0 leaves F clear", func(t *testing.T) {
+		h := h
+		h.Checksum = 0
+		assert.Zero(t, HLE(model.DMG0, h).CPU.F)
+		assert.Zero(t, HLE(model.DMG0, h).HRAM)
+	})
+
+	t.Run("unused IO reads $FF", func(t *testing.T) {
+		hw := HLE(model.DMG, h)
+		for _, addr := range []uint16{0xFF03, 0xFF08, 0xFF0E, 0xFF15, 0xFF1F, 0xFF27, 0xFF2F, 0xFF4C, 0xFF7F} {
+			assert.EqualValuesf(t, 0xFF, hw.IO[addr-0xFF00], "$%04X", addr)
+		}
+	})
+
+	t.Run("CGB compatibility mode", func(t *testing.T) {
+		h := h
+		h.Title[15] = 0x80 // CGB support
+		hw := HLE(model.CGB, h)
+		assert.False(t, hw.DMGCompat)
+		assert.Equal(t, [4]uint16{0x7FFF, 0x7FFF, 0x7FFF, 0x7FFF}, hw.BGPalettes[7])
+		assert.Zero(t, hw.OBJPalettes[0])
+
+		h.Title[15] = 0x00
+		hw = HLE(model.CGB, h)
+		assert.True(t, hw.DMGCompat)
+		p := CompatPaletteFor(h)
+		assert.Equal(t, p.BG, hw.BGPalettes[0])
+		assert.Equal(t, p.OBJ0, hw.OBJPalettes[0])
+		assert.Equal(t, p.OBJ1, hw.OBJPalettes[1])
+		assert.EqualValues(t, 0x11, hw.CPU.A)
+		assert.Zero(t, hw.CPU.B, "not published by Nintendo")
+		assert.EqualValues(t, 0x007C, hw.CPU.R16(cpu.HL))
+
+		h.OldLicensee = cartridge.Nintendo
+		copy(h.Title[:], "TETRIS")
+		hw = HLE(model.CGB, h)
+		assert.Equal(t, TitleChecksum(h), hw.CPU.B)
+		hw = HLE(model.AGB, h)
+		assert.Equal(t, TitleChecksum(h)+1, hw.CPU.B)
+
+		h.Title = cartridge.Title{0x43}
+		hw = HLE(model.CGB, h)
+		assert.EqualValues(t, 0x43, hw.CPU.B)
+		assert.EqualValues(t, 0x991A, hw.CPU.R16(cpu.HL))
+	})
+}
+
+// TestHLE_logoLoop runs the logo loop of the DMG boot rom, checking the
+// VRAM & stack it leaves match HLE's.
+func TestHLE_logoLoop(t *testing.T) {
+	bus := newCartridge(t)
+	copy(bus.mem[0x0027:], []uint8{
+		0x1A,             // ld a, (de)
+		0xCD, 0x95, 0x00, // call $0095
+		0xCD, 0x96, 0x00, // call $0096
+		0x13,       // inc de
+		0x7B,       // ld a, e
+		0xFE, 0x34, // cp $34
+		0x20, 0xF3, // jr nz, $0027
+	})
+	copy(bus.mem[0x0095:], []uint8{
+		0x4F,       // ld c, a
+		0x06, 0x04, // ld b, $04
+		0xC5,       // push bc
+		0xCB, 0x11, // rl c
+		0x17,       // rla
+		0xC1,       // pop bc
+		0xCB, 0x11, // rl c
+		0x17,       // rla
+		0x05,       // dec b
+		0x20, 0xF5, // jr nz, $0098
+		0x22, // ld (hl+), a
+		0x23, // inc hl
+		0x22, // ld (hl+), a
+		0x23, // inc hl
+		0xC9, // ret
+	})
+	c := cpu.NewCore(bus)
+	c.State = cpu.State{IR: bus.mem[0x0027], PC: 0x0028, SP: 0xFFFE}
+	c.R16Set(cpu.DE, 0x0104)
+	c.R16Set(cpu.HL, 0x8010)
+
+	_, err := c.RunUntil(0x0034, 100_000)
+	require.NoError(t, err)
+	require.EqualValues(t, 0x0034, c.PC-1)
+
+	hw := HLE(model.DMG, cartridge.Header{Logo: cartridge.NintendoLogo})
+	assert.Equal(t, bus.mem[0x8000:0x8190], hw.VRAM[:0x190])
+	assert.Equal(t, bus.mem[0xFF80:0xFFFF], hw.HRAM[:])
+}
@@ -25,10 +25,14 @@ func (e HeaderTruncatedError) Error() string {
 }
 
 type Header struct {
-	Title Title
-	MBC   MBCType
-	ROM   ROMSize
-	RAM   RAMSize
+	Logo        Logo
+	Title       Title
+	NewLicensee NewLicensee
+	MBC         MBCType
+	ROM         ROMSize
+	RAM         RAMSize
+	OldLicensee OldLicensee
+	Checksum    HeaderChecksum
 }
 
 func (h Header) LogValue() slog.Value {
@@ -51,10 +55,14 @@ func (h Header) LogValue() slog.Value {
 func ReadHeader(cartridge Cartridge) (Header, error) {
 	var h Header
 	return h, multierr.Combine(
+		ReadHeaderValue(cartridge, &h.Logo),
 		ReadHeaderValue(cartridge, &h.Title),
+		ReadHeaderValue(cartridge, &h.NewLicensee),
 		ReadHeaderValue(cartridge, &h.MBC),
 		ReadHeaderValue(cartridge, &h.ROM),
 		ReadHeaderValue(cartridge, &h.RAM),
+		ReadHeaderValue(cartridge, &h.OldLicensee),
+		ReadHeaderValue(cartridge, &h.Checksum),
 	)
 }
 
@@ -62,7 +70,7 @@ func ReadHeader(cartridge Cartridge) (Header, error) {
 // into the passed-in value pointer. If the passed-in pointer is nil, ReadHeaderValue panics.
 // HeaderTruncatedError is returned if the data isn't available (truncated header).
 func ReadHeaderValue[T interface {
-	Title | MBCType | ROMSize | RAMSize
+	Logo | Title | NewLicensee | MBCType | ROMSize | RAMSize | OldLicensee | HeaderChecksum
 }](
 	cartridge Cartridge,
 	value *T,
@@ -93,19 +101,55 @@ func ReadHeaderValue[T interface {
 	}
 
 	switch ptr := any(value).(type) {
+	case *Logo:
+		return rs((*ptr)[:], 0x104)
 	case *Title:
 		return rs((*ptr)[:], 0x134)
+	case *NewLicensee:
+		return rs((*ptr)[:], 0x144)
 	case *MBCType:
 		return rb((*byte)(ptr), 0x147)
 	case *ROMSize:
 		return rb((*byte)(ptr), 0x148)
 	case *RAMSize:
 		return rb((*byte)(ptr), 0x149)
+	case *OldLicensee:
+		return rb((*byte)(ptr), 0x14B)
+	case *HeaderChecksum:
+		return rb((*byte)(ptr), 0x14D)
 	default:
 		panic("unknown header field")
 	}
 }
 
+// Logo is the cartridge header's logo bitmap, which the boot rom checks
+// against NintendoLogo before starting the cartridge.
+type Logo [48]byte
+
+// NintendoLogo is the logo the boot rom requires.
+var NintendoLogo = Logo{
+	0xCE, 0xED, 0x66, 0x66, 0xCC, 0x0D, 0x00, 0x0B, 0x03, 0x73, 0x00, 0x83, 0x00, 0x0C, 0x00, 0x0D,
+	0x00, 0x08, 0x11, 0x1F, 0x88, 0x89, 0x00, 0x0E, 0xDC, 0xCC, 0x6E, 0xE6, 0xDD, 0xDD, 0xD9, 0x99,
+	0xBB, 0xBB, 0x67, 0x63, 0x6E, 0x0E, 0xEC, 0xCC, 0xDD, 0xDC, 0x99, 0x9F, 0xBB, 0xB9, 0x33, 0x3E,
+}
+
+// HeaderChecksum is the cartridge header's checksum of the bytes $0134-$014C,
+// which the boot rom checks before starting the cartridge.
+type HeaderChecksum uint8
+
+// ComputeHeaderChecksum returns the checksum the header of the cartridge should have.
+// If the cartridge data is too short, HeaderTruncatedError is returned.
+func ComputeHeaderChecksum(cartridge Cartridge) (HeaderChecksum, error) {
+	if len(cartridge) < 0x14D {
+		return 0, HeaderTruncatedError{HeaderDataLength: len(cartridge), WantedIndex: 0x134, WantedLength: 0x19}
+	}
+	var sum uint8
+	for _, b := range cartridge[0x134:0x14D] {
+		sum = sum - b - 1
+	}
+	return HeaderChecksum(sum), nil
+}
+
 // Title maps the cartridge header's title bytes field to a native string.
 type Title [16]byte
 
@@ -122,6 +166,28 @@ func (t Title) String() string {
 	return s.String()
 }
 
+// OldLicensee is the cartridge header's old licensee code, a single byte.
+// UseNewLicensee means the publisher is given by the new licensee code instead.
+type OldLicensee uint8
+
+const (
+	Nintendo       OldLicensee = 0x01
+	UseNewLicensee OldLicensee = 0x33
+)
+
+// NewLicensee is the cartridge header's new licensee code, two ASCII characters.
+type NewLicensee [2]byte
+
+func (l NewLicensee) String() string {
+	return string(l[:])
+}
+
+// Nintendo reports whether the licensee codes of the header name Nintendo as
+// the publisher, as the CGB boot rom checks before colorizing by title.
+func (h Header) Nintendo() bool {
+	return h.OldLicensee == Nintendo || h.OldLicensee == UseNewLicensee && h.NewLicensee == NewLicensee{'0', '1'}
+}
+
 // MBCType maps the cartridge header's type field to a cartridge/MBC type.
 type MBCType uint8
 
@@ -0,0 +1,48 @@
+// Opdeflint checks the CPU's YAML opcode definitions.
+//
+// Usage:
+//
+//	opdeflint [dir]
+//
+// dir defaults to cpu/defs. Problems are printed located by file & line, and
+// the exit status is 1 if there were any.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/wmarshpersonal/gogeebee/cpu/opdeflint"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("opdeflint: ")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: opdeflint [dir]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := filepath.Join("cpu", "defs")
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	problems, err := opdeflint.LintFS(os.DirFS(dir))
+	if err != nil {
+		log.Fatalf("%s%c%v", dir, filepath.Separator, err)
+	}
+	for _, p := range problems {
+		if p.File != "" {
+			p.File = filepath.Join(dir, p.File)
+		}
+		fmt.Println(p)
+	}
+	if len(problems) > 0 {
+		os.Exit(1)
+	}
+}
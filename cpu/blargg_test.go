@@ -63,70 +63,22 @@ func (suite *BlarggTestSuite) TestROMs() {
 				}()
 
 				defer serialWriter.Close()
-				state := *NewResetState()
-				// timer
-				timer := gb.DMGTimer()
+				bus := &blarggBus{
+					mem:    &mem,
+					timer:  gb.DMGTimer(),
+					serial: serialWriter,
+				}
+				core := NewCore(bus)
 				for {
 					select {
 					case <-ctx.Done():
 						return
 					default:
-						var cycle Cycle
-						state, cycle = NextCycle(state)
-
-						timer = timer.Step()
-						if timer.IR {
-							state.IF |= 0b100
+						if _, err := core.RunCycles(1000); err != nil {
+							suite.FailNow("cpu error", err)
 						}
-
-						if !state.Halted {
-							state, cycle = StartCycle(state, cycle)
-							addr := cycle.Addr.Do(state)
-							var data uint8
-							if cycle.Data.RD() {
-								switch addr {
-								case 0xFF44: // LY
-									data = 0x90
-								case 0xFF04: // DIV
-									data = timer.Read(gb.DIV)
-								case 0xFF05: // TIMA
-									data = timer.Read(gb.TIMA)
-								case 0xFF06: // TMA
-									data = timer.Read(gb.TMA)
-								case 0xFF07: // TAC
-									data = timer.Read(gb.TAC)
-								case 0xFF0F: // IF
-									data = state.IF
-								case 0xFFFF: // IE
-									data = state.IE
-								default:
-									data = mem[addr]
-								}
-							}
-
-							wr, wrData := cycle.Data.WR(state, state.IR)
-							if wr {
-								switch addr {
-								case 0xFF01: // serial
-									fmt.Fprintf(serialWriter, "%c", rune(wrData))
-								case 0xFF04: // DIV
-									timer = timer.Write(gb.DIV, wrData)
-								case 0xFF05: // TIMA
-									timer = timer.Write(gb.TIMA, wrData)
-								case 0xFF06: // TMA
-									timer = timer.Write(gb.TMA, wrData)
-								case 0xFF07: // TAC
-									timer = timer.Write(gb.TAC, wrData)
-								case 0xFF0F: // IF
-									state.IF = wrData & 0x1F
-								case 0xFFFF: // IE
-									state.IE = wrData & 0x1F
-								default:
-									mem[addr] = wrData
-								}
-							}
-
-							state = FinishCycle(state, cycle, data)
+						if core.Signals&DIVReset != 0 {
+							bus.timer = bus.timer.Write(gb.DIV, 0)
 						}
 					}
 				}
@@ -148,6 +100,63 @@ func (suite *BlarggTestSuite) TestROMs() {
 	}
 }
 
+// blarggBus maps the memory & IO needed by the blargg test ROMs.
+type blarggBus struct {
+	mem    *[0x10000]byte
+	timer  gb.Timer
+	irq    uint8
+	serial io.Writer
+}
+
+func (b *blarggBus) Tick() {
+	b.timer = b.timer.Step()
+	if b.timer.IR {
+		b.irq |= IntTimer
+	}
+}
+
+func (b *blarggBus) Read(addr uint16) uint8 {
+	b.Tick()
+	switch addr {
+	case 0xFF44: // LY
+		return 0x90
+	case 0xFF04: // DIV
+		return b.timer.Read(gb.DIV)
+	case 0xFF05: // TIMA
+		return b.timer.Read(gb.TIMA)
+	case 0xFF06: // TMA
+		return b.timer.Read(gb.TMA)
+	case 0xFF07: // TAC
+		return b.timer.Read(gb.TAC)
+	default:
+		return b.mem[addr]
+	}
+}
+
+func (b *blarggBus) Write(addr uint16, v uint8) {
+	b.Tick()
+	switch addr {
+	case 0xFF01: // serial
+		fmt.Fprintf(b.serial, "%c", rune(v))
+	case 0xFF04: // DIV
+		b.timer = b.timer.Write(gb.DIV, v)
+	case 0xFF05: // TIMA
+		b.timer = b.timer.Write(gb.TIMA, v)
+	case 0xFF06: // TMA
+		b.timer = b.timer.Write(gb.TMA, v)
+	case 0xFF07: // TAC
+		b.timer = b.timer.Write(gb.TAC, v)
+	default:
+		b.mem[addr] = v
+	}
+}
+
+func (b *blarggBus) Interrupts() uint8 {
+	irq := b.irq
+	b.irq = 0
+	return irq
+}
+
 type BlarggTestSuite struct {
 	suite.Suite
 	roms *zip.ReadCloser
@@ -0,0 +1,173 @@
+package cpu
+
+// Core drives the CPU's micro-op pipeline against a bus.
+type Core struct {
+	State
+	Bus Bus
+
+	// InstructionSet is the set of opcodes the core runs, or nil for the default set.
+	InstructionSet *InstructionSet
+
+	// Fast selects the threaded interpreter, which runs opcodes pre-decoded from
+	// the instruction set on the state in place. It runs cycle-for-cycle
+	// identically to the pipeline, for a fraction of the cost.
+	Fast bool
+
+	// Tracer, if set, is given the state before every cycle.
+	Tracer *Tracer
+
+	// CycleObserver, if set, is given a record of every cycle run, such as
+	// to a CycleTracer.
+	CycleObserver CycleObserver
+
+	cycles int // cycles run, for CycleRecord.N
+
+	fetch   uint16 // address the opcode in IR was fetched from...
+	fetched bool   // ...once the core has run a fetch
+}
+
+// NewCore returns a core attached to the bus, in the state after the boot rom has run.
+func NewCore(bus Bus) *Core {
+	return &Core{
+		State: *NewResetState(),
+		Bus:   bus,
+	}
+}
+
+// Step runs a single M-cycle. See the package-level Step for the errors returned.
+func (c *Core) Step() error {
+	_, err := c.step()
+	return err
+}
+
+// step runs a single M-cycle, tracing it.
+func (c *Core) step() (info cycleInfo, err error) {
+	if c.Tracer != nil {
+		var peek ReadMemFunc
+		if p, ok := c.Bus.(Peeker); ok {
+			peek = p.Peek
+		}
+		pc := c.PC - 1
+		if c.fetched {
+			pc = c.fetch
+		}
+		if err := c.Tracer.Trace(c.State, pc, peek); err != nil {
+			return info, err
+		}
+	}
+
+	before := c.State
+	if info, err = c.run(); err != nil {
+		return info, err
+	}
+	if info.Cycle.Fetch {
+		c.fetch, c.fetched = info.Addr, true
+	}
+	if c.CycleObserver != nil {
+		err = c.CycleObserver.ObserveCycle(newCycleRecord(c.cycles, before, c.State, info), c.State)
+	}
+	c.cycles++
+	return info, err
+}
+
+// run runs a single M-cycle, with the interpreter selected by Fast.
+func (c *Core) run() (info cycleInfo, err error) {
+	if !c.Fast {
+		c.State, info, err = c.instructionSet().step(c.State, c.Bus)
+		return info, err
+	}
+
+	s := c.State
+	defer func() {
+		if r := recover(); r != nil {
+			c.State, info, err = s, cycleInfo{}, faultError(r, Fault{PC: s.PC, IR: s.IR, CB: s.CB, S: s.S})
+		}
+	}()
+	return c.instructionSet().fastStep(&c.State, c.Bus), nil
+}
+
+func (c *Core) instructionSet() *InstructionSet {
+	if c.InstructionSet == nil {
+		return defaultInstructionSet
+	}
+	return c.InstructionSet
+}
+
+// RunCycles runs up to n M-cycles, returning the number of cycles run.
+// It returns early after a cycle that raised Signals, so the caller can act
+// on them, or on an error.
+func (c *Core) RunCycles(n int) (int, error) {
+	for i := range n {
+		if err := c.Step(); err != nil {
+			return i, err
+		}
+		if c.Signals != 0 {
+			return i + 1, nil
+		}
+	}
+	return n, nil
+}
+
+// StepResult describes the cycles run by an instruction-level step.
+type StepResult struct {
+	Cycles    int      // M-cycles consumed
+	Accesses  []Access // memory accesses, in order
+	Interrupt bool     // the step was an interrupt dispatch
+	Fetched   bool     // the step ended fetching the next opcode...
+	Next      uint16   // ...from this address
+}
+
+// StepInstruction runs the CPU to the end of the current instruction: the cycle
+// that fetches the next opcode, where S resets to 0, or the cycle after which
+// the CPU is idle. An interrupt dispatch is a step of its own. While the CPU is
+// idle (halted, stopped or locked) a step is a single idle cycle.
+func (c *Core) StepInstruction() (StepResult, error) {
+	var res StepResult
+	for {
+		info, err := c.step()
+		if err != nil {
+			return res, err
+		}
+		res.Cycles++
+		if info.Accessed {
+			res.Accesses = append(res.Accesses, info.Access)
+		}
+		if res.Cycles == 1 {
+			res.Interrupt = c.Interrupting
+		}
+		if info.Cycle.Fetch {
+			res.Fetched, res.Next = true, info.Addr
+		}
+		if info.Idle || info.Cycle.Fetch || c.Idle() {
+			return res, nil
+		}
+	}
+}
+
+// RunUntil runs whole instructions until the next instruction to execute is at pc,
+// or at least maxCycles M-cycles have been run. At least one instruction is run.
+// The returned result covers all of the instructions run.
+//
+// The next instruction is the one whose opcode was fetched at the end of the last
+// instruction, unless an interrupt dispatch is about to start instead. PC-1 isn't
+// used, as PC isn't advanced past the opcode by the HALT bug.
+func (c *Core) RunUntil(pc uint16, maxCycles int) (StepResult, error) {
+	var res StepResult
+	for {
+		step, err := c.StepInstruction()
+		res.Cycles += step.Cycles
+		res.Accesses = append(res.Accesses, step.Accesses...)
+		res.Interrupt = res.Interrupt || step.Interrupt
+		res.Fetched, res.Next = step.Fetched, step.Next
+		if err != nil || res.Cycles >= maxCycles {
+			return res, err
+		}
+		if step.Fetched && step.Next == pc {
+			next := c.State
+			beginCycle(&next)
+			if !next.Interrupting {
+				return res, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package cpu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingBus counts bus calls and raises an interrupt after a number of cycles.
+type countingBus struct {
+	flatBus
+	reads, writes, ticks int
+	irqAt                int
+	irq                  uint8
+}
+
+func (b *countingBus) cycle() {
+	if b.reads+b.writes+b.ticks == b.irqAt {
+		b.irq |= IntTimer
+	}
+}
+
+func (b *countingBus) Read(addr uint16) uint8 {
+	b.reads++
+	b.cycle()
+	return b.flatBus.Read(addr)
+}
+
+func (b *countingBus) Write(addr uint16, v uint8) {
+	b.writes++
+	b.cycle()
+	b.flatBus.Write(addr, v)
+}
+
+func (b *countingBus) Tick() {
+	b.ticks++
+	b.cycle()
+}
+
+func (b *countingBus) Interrupts() uint8 {
+	irq := b.irq
+	b.irq = 0
+	return irq
+}
+
+func TestCore(t *testing.T) {
+	t.Run("bus is clocked once per cycle", func(t *testing.T) {
+		assert := assert.New(t)
+		bus := &countingBus{irqAt: -1}
+		copy(bus.flatBus[0x100:], []uint8{
+			0xC5, // push bc
+			0x00, // nop
+		})
+		c := NewCore(bus)
+		c.IR, c.S, c.PC = bus.flatBus[0x100], 0, 0x0101
+
+		n, err := c.RunCycles(4 + 1)
+		assert.NoError(err)
+		assert.Equal(5, n)
+		assert.Equal(2, bus.reads)
+		assert.Equal(2, bus.writes)
+		assert.Equal(1, bus.ticks)
+	})
+
+	t.Run("interrupt source wakes HALT", func(t *testing.T) {
+		assert := assert.New(t)
+		bus := &countingBus{irqAt: 100}
+		copy(bus.flatBus[0x100:], []uint8{
+			0x76, // halt
+			0x3C, // inc a
+		})
+		c := NewCore(bus)
+		c.IR, c.S, c.PC, c.A, c.IF = bus.flatBus[0x100], 0, 0x0101, 0, 0
+		c.IE = IntTimer
+
+		_, err := c.RunCycles(100)
+		assert.NoError(err)
+		assert.True(c.Halted)
+
+		_, err = c.RunCycles(2)
+		assert.NoError(err)
+		assert.False(c.Halted)
+		assert.EqualValues(1, c.A)
+		assert.EqualValues(IntTimer, c.IF)
+	})
+
+	t.Run("unused IE & IF bits don't request interrupts", func(t *testing.T) {
+		for _, fast := range []bool{false, true} {
+			assert := assert.New(t)
+			bus := &countingBus{irqAt: -1}
+			bus.flatBus[0x0040] = 0xD9 // reti
+			copy(bus.flatBus[0x100:], []uint8{
+				0x3E, 0xFF, // ld a, $FF
+				0xE0, 0xFF, // ldh ($FF), a
+				0xFB, // ei
+				0x00, // nop
+				0x00, // nop
+				0x76, // halt
+				0x3C, // inc a
+			})
+			c := NewCore(bus)
+			c.IR, c.S, c.PC, c.IF, c.Fast = bus.flatBus[0x100], 0, 0x0101, 0xE0, fast
+
+			_, err := c.RunCycles(2 + 3 + 1 + 1 + 1 + 1 + 100)
+			assert.NoError(err)
+			assert.EqualValuesf(0xFF, c.IE, "fast: %v", fast)
+			assert.Truef(c.IME, "fast: %v", fast)
+			assert.Truef(c.Halted, "fast: %v: HALT isn't woken", fast)
+			assert.EqualValuesf(0x0109, c.PC, "fast: %v: no interrupt was dispatched", fast)
+		}
+	})
+
+	t.Run("RunCycles returns early on signals", func(t *testing.T) {
+		assert := assert.New(t)
+		bus := &countingBus{irqAt: -1}
+		copy(bus.flatBus[0x100:], []uint8{
+			0x00,       // nop
+			0x10, 0x00, // stop
+		})
+		c := NewCore(bus)
+		c.IR, c.S, c.PC = bus.flatBus[0x100], 0, 0x0101
+
+		n, err := c.RunCycles(100)
+		assert.NoError(err)
+		assert.Equal(2, n)
+		assert.Equal(DIVReset, c.Signals)
+		assert.True(c.Stopped)
+	})
+}
+
+func TestCore_StepInstruction(t *testing.T) {
+	assert := assert.New(t)
+	bus := &countingBus{irqAt: -1}
+	copy(bus.flatBus[0x100:], []uint8{
+		0x3E, 0x42, // ld a, $42
+		0xC5,       // push bc
+		0xCB, 0x7F, // bit 7, a
+		0xC2, 0x00, 0x02, // jp nz, $0200
+		0xFB, // ei
+		0x00, // nop
+	})
+	c := NewCore(bus)
+	c.IR, c.S, c.PC, c.IF, c.IE = bus.flatBus[0x100], 0, 0x0101, 0, IntVBlank
+	c.B, c.C, c.SP = 0x12, 0x34, 0xD000
+
+	steps := []StepResult{
+		{Cycles: 2, Accesses: []Access{{0x0101, 0x42, false}, {0x0102, 0xC5, false}}, Fetched: true, Next: 0x0102},
+		{Cycles: 4, Accesses: []Access{{0xCFFF, 0x12, true}, {0xCFFE, 0x34, true}, {0x0103, 0xCB, false}}, Fetched: true, Next: 0x0103},
+		{Cycles: 2, Accesses: []Access{{0x0104, 0x7F, false}, {0x0105, 0xC2, false}}, Fetched: true, Next: 0x0105},
+		{Cycles: 3, Accesses: []Access{{0x0106, 0x00, false}, {0x0107, 0x02, false}, {0x0108, 0xFB, false}}, Fetched: true, Next: 0x0108},
+		{Cycles: 1, Accesses: []Access{{0x0109, 0x00, false}}, Fetched: true, Next: 0x0109},
+		{Cycles: 1, Accesses: []Access{{0x010A, 0x00, false}}, Fetched: true, Next: 0x010A},
+	}
+	for i, want := range steps {
+		got, err := c.StepInstruction()
+		assert.NoError(err)
+		assert.Equalf(want, got, "step %d", i)
+	}
+
+	// interrupt dispatch is a step of its own
+	c.IF = IntVBlank
+	got, err := c.StepInstruction()
+	assert.NoError(err)
+	assert.Equal(StepResult{
+		Cycles:    5,
+		Interrupt: true,
+		Accesses:  []Access{{0xCFFD, 0x01, true}, {0xCFFC, 0x0A, true}, {0x0040, 0x00, false}},
+		Fetched:   true,
+		Next:      0x0040,
+	}, got)
+}
+
+func TestCore_RunUntil(t *testing.T) {
+	t.Run("loop", func(t *testing.T) {
+		assert := assert.New(t)
+		bus := &countingBus{irqAt: -1}
+		copy(bus.flatBus[0x100:], []uint8{
+			0x04,       // inc b
+			0x20, 0xFD, // jr nz, -3
+			0x3C, // inc a
+		})
+		c := NewCore(bus)
+		c.IR, c.S, c.PC, c.B = bus.flatBus[0x100], 0, 0x0101, 0xFE
+
+		res, err := c.RunUntil(0x0103, 1000)
+		assert.NoError(err)
+		assert.EqualValues(0x0103, c.PC-1)
+		assert.Equal(1+3+1+2, res.Cycles)
+		assert.Len(res.Accesses, 1+2+1+2)
+
+		res, err = c.RunUntil(0x0000, 10)
+		assert.NoError(err)
+		assert.GreaterOrEqual(res.Cycles, 10)
+	})
+
+	t.Run("HALT bug", func(t *testing.T) {
+		assert := assert.New(t)
+		bus := &countingBus{irqAt: -1}
+		copy(bus.flatBus[0x100:], []uint8{
+			0x76, // halt
+			0x3C, // inc a, run twice
+		})
+		c := NewCore(bus)
+		c.IR, c.S, c.PC, c.A = bus.flatBus[0x100], 0, 0x0101, 0
+		c.IME, c.IF, c.IE = false, IntVBlank, IntVBlank
+
+		res, err := c.RunUntil(0x0101, 100)
+		assert.NoError(err)
+		assert.Equal(1, res.Cycles, "inc a is next, though PC-1 is $0100")
+		assert.EqualValues(0x0101, c.PC)
+
+		res, err = c.RunUntil(0x0101, 100)
+		assert.NoError(err)
+		assert.Equal(1, res.Cycles, "inc a is next again")
+		assert.EqualValues(1, c.A)
+	})
+
+	t.Run("interrupt dispatch", func(t *testing.T) {
+		assert := assert.New(t)
+		bus := &countingBus{irqAt: -1}
+		bus.flatBus[0x0040] = 0xD9 // reti
+		copy(bus.flatBus[0x100:], []uint8{
+			0xFB, // ei
+			0x00, // nop
+			0x00, // nop, preempted by the interrupt
+		})
+		c := NewCore(bus)
+		c.IR, c.S, c.PC, c.SP = bus.flatBus[0x100], 0, 0x0101, 0xD000
+		c.IF, c.IE = IntVBlank, IntVBlank
+
+		res, err := c.RunUntil(0x0102, 100)
+		assert.NoError(err)
+		assert.True(res.Interrupt)
+		assert.Equal(1+1+5+4, res.Cycles, "runs on through the interrupt handler")
+		assert.True(res.Fetched)
+		assert.EqualValues(0x0102, res.Next)
+	})
+}
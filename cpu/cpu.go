@@ -1,9 +1,5 @@
 package cpu
 
-import (
-	"fmt"
-)
-
 type Condition int
 
 const (
@@ -29,6 +25,32 @@ func (c Condition) Test(f uint8) bool {
 	}
 }
 
+// Interrupt bits, as laid out in the IE & IF registers.
+const (
+	IntVBlank uint8 = 1 << iota
+	IntLCD
+	IntTimer
+	IntSerial
+	IntJoypad
+)
+
+// Signal is a set of one-shot outputs the CPU raises for the host to act on.
+// Signals are held in State for the remainder of the cycle that raised them,
+// and are cleared by the next call to NextCycle.
+type Signal uint8
+
+const (
+	DIVReset      Signal = 1 << iota // STOP was executed; the DIV counter must be reset
+	SpeedSwitched                    // STOP performed a CGB speed switch
+	LockedUp                         // an illegal opcode locked up the CPU
+)
+
+// SpeedSwitchCycles is how long, in M-cycles, a CGB speed switch pauses the CPU
+// for on hardware. The CPU doesn't model the pause: DIV & the timer are stopped
+// during it, so idle cycles clocking the bus would be wrong. A host handling
+// SpeedSwitched must hold the CPU for these cycles itself, without clocking DIV.
+const SpeedSwitchCycles = 2050
+
 type ReadMemFunc func(uint16) uint8
 type WriteMemFunc func(uint16, uint8)
 
@@ -55,15 +77,18 @@ func init() {
 		Addr: AddrSP,
 		IDU:  Dec,
 	})
+	// the vector is chosen after PCH has been pushed: if the push
+	// overwrote IE and cancelled the interrupt, dispatch jumps to $0000
 	interruptOpcode = append(interruptOpcode, Cycle{
 		Addr: AddrSP,
 		IDU:  Dec,
 		Data: WritePCH,
+		Misc: IRQ,
 	})
 	interruptOpcode = append(interruptOpcode, Cycle{
 		Addr: AddrSP,
 		Data: WritePCL,
-		IDU:  IRQ,
+		Misc: PC_Equals_WZ,
 	})
 	interruptOpcode = append(interruptOpcode, Cycle{
 		Addr:  AddrPC,
@@ -71,20 +96,16 @@ func init() {
 	})
 }
 
+// NextCycle returns the next cycle to run from the state, using the default instruction set.
 func NextCycle(s State) (State, Cycle) {
-	if s.Halted {
-		if s.IF&s.IE != 0 {
-			s.Halted = false
-		} else {
-			return s, Cycle{}
-		}
-	}
+	return defaultInstructionSet.NextCycle(s)
+}
 
-	if s.S == 0 {
-		if s.IME && (s.IF&s.IE) != 0 {
-			s.IME = false
-			s.Interrupting = true
-		}
+// NextCycle returns the next cycle to run from the state.
+func (is *InstructionSet) NextCycle(s State) (State, Cycle) {
+	beginCycle(&s)
+	if s.Idle() {
+		return s, Cycle{}
 	}
 
 	if s.Interrupting {
@@ -105,21 +126,62 @@ func NextCycle(s State) (State, Cycle) {
 
 	var cycleIndex = s.S
 	if s.CB {
-		operation = operationsCB[opcode]
+		operation = is.opsCB[opcode]
 		cycleIndex--
 	} else {
-		operation = operations[opcode]
+		operation = is.ops[opcode]
 	}
 	if operation == nil {
-		panic(fmt.Sprintf("unimplemented opcode $%02X", opcode))
+		panic(InvalidOpcodeError{})
+	}
+	if cycleIndex >= len(operation) {
+		panic(InvalidStateError{Reason: "cycle-step out of range"})
 	}
 
 	return s, operation[cycleIndex]
 }
 
+// beginCycle clears the signals of the last cycle, wakes an idle CPU if it
+// should be, and starts an interrupt dispatch at an instruction boundary, in place.
+func beginCycle(s *State) {
+	s.Signals = 0
+
+	if s.Locked {
+		return
+	}
+
+	if s.Stopped {
+		// only a joypad press wakes the CPU from STOP
+		if s.IF&IntJoypad == 0 {
+			return
+		}
+		s.Stopped = false
+	}
+
+	if s.Halted {
+		if s.Pending() == 0 {
+			return
+		}
+		s.Halted = false
+	}
+
+	if s.S == 0 {
+		if s.IME && s.Pending() != 0 {
+			s.IME = false
+			s.Interrupting = true
+		}
+	}
+}
+
 func StartCycle(s State, cycle Cycle) (State, Cycle) {
 	if s.Halted {
-		panic("halted")
+		panic(InvalidStateError{Reason: "halted"})
+	}
+	if s.Stopped {
+		panic(InvalidStateError{Reason: "stopped"})
+	}
+	if s.Locked {
+		panic(InvalidStateError{Reason: "locked"})
 	}
 
 	s.S++ // increment state
@@ -127,7 +189,7 @@ func StartCycle(s State, cycle Cycle) (State, Cycle) {
 	if cycle.Fetch {
 		// add fetch to cycle
 		if cycle.Data != 0 || cycle.IDU != 0 {
-			panic("not enough free ops for fetch")
+			panic(InvalidStateError{Reason: "not enough free ops for fetch"})
 		}
 		cycle.Data = ReadIR
 		cycle.IDU = IncSetPC
@@ -145,15 +207,29 @@ func StartCycle(s State, cycle Cycle) (State, Cycle) {
 
 func FinishCycle(s State, cycle Cycle, data uint8) State {
 	if s.Halted {
-		panic("halted")
+		panic(InvalidStateError{Reason: "halted"})
+	}
+	if s.Stopped {
+		panic(InvalidStateError{Reason: "stopped"})
+	}
+	if s.Locked {
+		panic(InvalidStateError{Reason: "locked"})
 	}
 
 	opcode := s.IR
+	imePending := s.IMEPending
 
 	// run fixed pipeline:
 	s = cycle.Data.Do(s, data)
 	s = cycle.IDU.Do(s, cycle.Addr)
 	s = cycle.Misc.Do(s, opcode)
 
+	// a pending EI takes effect when the instruction after it completes,
+	// unless that instruction cancelled it (DI)
+	if cycle.Fetch && imePending && s.IMEPending {
+		s.IME = true
+		s.IMEPending = false
+	}
+
 	return s
 }
@@ -0,0 +1,293 @@
+package cpu
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testMachine is a minimal host for running short programs out of flat memory.
+type testMachine struct {
+	State
+	mem [0x10000]uint8
+}
+
+// newTestMachine loads the program at $0100 and prefetches its first opcode.
+func newTestMachine(program ...uint8) *testMachine {
+	m := &testMachine{}
+	copy(m.mem[0x100:], program)
+	m.PC = 0x0101
+	m.SP = 0xFFFE
+	m.IR = m.mem[0x100]
+	return m
+}
+
+// cycle runs one M-cycle.
+func (m *testMachine) cycle() {
+	var cycle Cycle
+	m.State, cycle = NextCycle(m.State)
+	if m.Idle() {
+		return
+	}
+	m.State, cycle = StartCycle(m.State, cycle)
+	addr := cycle.Addr.Do(m.State)
+	var data uint8
+	if cycle.Data.RD() {
+		switch addr {
+		case 0xFF0F:
+			data = m.IF
+		case 0xFFFF:
+			data = m.IE
+		default:
+			data = m.mem[addr]
+		}
+	}
+	if wr, v := cycle.Data.WR(m.State, m.IR); wr {
+		switch addr {
+		case 0xFF0F:
+			m.IF = v & 0x1F
+		case 0xFFFF:
+			m.IE = v & 0x1F
+		default:
+			m.mem[addr] = v
+		}
+	}
+	m.State = FinishCycle(m.State, cycle, data)
+}
+
+// run runs n M-cycles.
+func (m *testMachine) run(n int) {
+	for range n {
+		m.cycle()
+	}
+}
+
+func TestStop(t *testing.T) {
+	t.Run("STOP skips its second byte and waits for joypad", func(t *testing.T) {
+		assert := assert.New(t)
+		m := newTestMachine(
+			0x10, 0x3C, // stop (with inc a as second byte)
+			0x3C, // inc a
+		)
+
+		m.cycle()
+		assert.True(m.Stopped)
+		assert.True(m.Signals&DIVReset != 0, "DIV reset should be signalled")
+		assert.EqualValues(0x0102, m.PC)
+
+		m.run(100)
+		assert.True(m.Stopped)
+		assert.Zero(m.Signals, "signals only last one cycle")
+		assert.EqualValues(0, m.A)
+
+		m.IF |= IntJoypad
+		m.run(2) // fetch, inc a
+		assert.False(m.Stopped)
+		assert.EqualValues(1, m.A)
+		assert.EqualValues(0x0104, m.PC)
+	})
+
+	t.Run("STOP performs an armed speed switch", func(t *testing.T) {
+		assert := assert.New(t)
+		m := newTestMachine(
+			0x10, 0x00, // stop
+			0x3C, // inc a
+		)
+		m.SpeedSwitch = true
+
+		m.cycle()
+		assert.False(m.Stopped)
+		assert.False(m.SpeedSwitch)
+		assert.True(m.DoubleSpeed)
+		assert.Equal(DIVReset|SpeedSwitched, m.Signals)
+
+		m.run(2) // fetch, inc a
+		assert.EqualValues(1, m.A)
+	})
+}
+
+func TestHalt(t *testing.T) {
+	t.Run("HALT waits for an interrupt with IME=0", func(t *testing.T) {
+		assert := assert.New(t)
+		m := newTestMachine(
+			0x76, // halt
+			0x3C, // inc a
+			0x00, // nop
+		)
+		m.IE = IntTimer
+
+		m.run(10)
+		assert.True(m.Halted)
+		assert.EqualValues(0, m.A)
+
+		m.IF |= IntTimer
+		m.cycle()
+		assert.False(m.Halted)
+		assert.EqualValues(1, m.A)
+		assert.EqualValues(0x0103, m.PC)
+	})
+
+	t.Run("HALT bug reads the next byte twice", func(t *testing.T) {
+		assert := assert.New(t)
+		m := newTestMachine(
+			0x76, // halt
+			0x3C, // inc a
+			0x00, // nop
+		)
+		m.IE = IntTimer
+		m.IF = IntTimer
+
+		m.cycle()
+		assert.False(m.Halted)
+		assert.EqualValues(0x0101, m.PC)
+
+		m.run(2)
+		assert.EqualValues(2, m.A, "inc a should have executed twice")
+		assert.EqualValues(0x0103, m.PC)
+	})
+
+	t.Run("HALT bug with an operand byte", func(t *testing.T) {
+		assert := assert.New(t)
+		m := newTestMachine(
+			0x76,       // halt
+			0x3E, 0x14, // ld a, $14 -> executes as ld a, $3E; inc d
+		)
+		m.IE = IntTimer
+		m.IF = IntTimer
+
+		m.run(4)
+		assert.EqualValues(0x3E, m.A)
+		assert.EqualValues(1, m.D)
+	})
+}
+
+func TestEI(t *testing.T) {
+	t.Run("EI enables interrupts after the next instruction", func(t *testing.T) {
+		assert := assert.New(t)
+		m := newTestMachine(
+			0xFB, // ei
+			0x00, // nop
+			0x00, // nop
+		)
+		m.IE = IntVBlank
+		m.IF = IntVBlank
+
+		m.cycle() // ei
+		assert.False(m.IME)
+		assert.True(m.IMEPending)
+
+		m.cycle() // nop
+		assert.True(m.IME)
+		assert.False(m.IMEPending)
+
+		m.run(5) // interrupt dispatch
+		assert.EqualValues(0x0041, m.PC)
+		assert.EqualValues(0xFFFC, m.SP)
+		assert.EqualValues(0x0102, uint16(m.mem[0xFFFD])<<8|uint16(m.mem[0xFFFC]))
+		assert.Zero(m.IF)
+	})
+
+	t.Run("EI; DI never services an interrupt", func(t *testing.T) {
+		assert := assert.New(t)
+		m := newTestMachine(
+			0xFB, // ei
+			0xF3, // di
+		)
+		m.IE = IntVBlank
+		m.IF = IntVBlank
+
+		m.run(10)
+		assert.False(m.IME)
+		assert.False(m.IMEPending)
+		assert.EqualValues(IntVBlank, m.IF)
+		assert.EqualValues(0xFFFE, m.SP)
+	})
+
+	t.Run("EI; HALT returns to the HALT", func(t *testing.T) {
+		assert := assert.New(t)
+		m := newTestMachine(
+			0xFB, // ei
+			0x76, // halt
+		)
+		m.IE = IntVBlank
+		m.IF = IntVBlank
+
+		m.run(2 + 5)
+		assert.EqualValues(0x0041, m.PC)
+		assert.EqualValues(0x0101, uint16(m.mem[0xFFFD])<<8|uint16(m.mem[0xFFFC]))
+	})
+}
+
+func TestInterruptDispatch(t *testing.T) {
+	// newPushMachine sets up an interrupt dispatch from PC=$hh01 which
+	// pushes its high byte into IE.
+	newPushMachine := func(pch uint8) *testMachine {
+		m := &testMachine{}
+		m.PC = uint16(pch)<<8 | 0x01
+		m.SP = 0x0000
+		m.IME = true
+		return m
+	}
+
+	t.Run("PCH push into IE cancels the interrupt", func(t *testing.T) {
+		assert := assert.New(t)
+		m := newPushMachine(0x02)
+		m.IE = IntVBlank
+		m.IF = IntVBlank
+
+		m.run(5)
+		assert.EqualValues(0x0001, m.PC, "dispatch should have jumped to $0000")
+		assert.EqualValues(0x02, m.IE)
+		assert.EqualValues(IntVBlank, m.IF, "cancelled interrupt should not be acknowledged")
+		assert.False(m.IME)
+	})
+
+	t.Run("PCH push into IE changes the vector", func(t *testing.T) {
+		assert := assert.New(t)
+		m := newPushMachine(0x02)
+		m.IE = IntVBlank | IntLCD
+		m.IF = IntVBlank | IntLCD
+
+		m.run(5)
+		assert.EqualValues(0x0049, m.PC)
+		assert.EqualValues(IntVBlank, m.IF)
+	})
+
+	t.Run("PCL push into IE is too late to cancel", func(t *testing.T) {
+		assert := assert.New(t)
+		m := newPushMachine(0x01)
+		m.SP = 0x0001 // PCH -> $0000, PCL -> $FFFF
+		m.PC = 0x0102
+		m.IE = IntVBlank
+		m.IF = IntVBlank
+
+		m.run(5)
+		assert.EqualValues(0x0041, m.PC)
+		assert.EqualValues(0x01, m.IE)
+		assert.Zero(m.IF)
+	})
+}
+
+func TestIllegalOpcodes(t *testing.T) {
+	for _, opcode := range []uint8{0xD3, 0xDB, 0xDD, 0xE3, 0xE4, 0xEB, 0xEC, 0xED, 0xF4, 0xFC, 0xFD} {
+		t.Run(fmt.Sprintf("$%02X locks up the CPU", opcode), func(t *testing.T) {
+			assert := assert.New(t)
+			m := newTestMachine(opcode)
+			m.IME = true
+			m.IE = IntVBlank
+
+			m.cycle()
+			assert.True(m.Locked)
+			assert.Equal(LockedUp, m.Signals)
+			assert.True(m.Idle())
+
+			m.IF = IntVBlank
+			m.run(100)
+			assert.True(m.Locked)
+			assert.Zero(m.Signals)
+			assert.EqualValues(0x0101, m.PC)
+			assert.EqualValues(0xFFFE, m.SP, "interrupts should not be serviced")
+		})
+	}
+}
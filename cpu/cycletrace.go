@@ -0,0 +1,190 @@
+package cpu
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CycleRecord is an M-cycle as run by the CPU.
+type CycleRecord struct {
+	N      int             `json:"n"`               // cycles run by the core before this one
+	S      int             `json:"s"`               // cycle-step the cycle ran at
+	Idle   bool            `json:"idle,omitempty"`  // the CPU was idle and executed nothing
+	Cycle  Cycle           `json:"cycle"`           // cycle as executed, with any fetch folded in
+	Addr   uint16          `json:"address"`         // address the Addr selector resolved to
+	Data   uint8           `json:"data"`            // byte read or written...
+	Read   bool            `json:"read,omitempty"`  // ...if the cycle read...
+	Write  bool            `json:"write,omitempty"` // ...or wrote memory
+	Deltas []RegisterDelta `json:"deltas,omitempty"`
+}
+
+// RegisterDelta is a register changed by a cycle.
+type RegisterDelta struct {
+	Reg string `json:"reg"`
+	Old uint16 `json:"old"`
+	New uint16 `json:"new"`
+}
+
+// registerDeltas returns the registers that differ between two states.
+func registerDeltas(before, after State) []RegisterDelta {
+	var deltas []RegisterDelta
+	for _, r := range []struct {
+		name     string
+		old, new uint16
+	}{
+		{"A", uint16(before.A), uint16(after.A)},
+		{"F", uint16(before.F), uint16(after.F)},
+		{"B", uint16(before.B), uint16(after.B)},
+		{"C", uint16(before.C), uint16(after.C)},
+		{"D", uint16(before.D), uint16(after.D)},
+		{"E", uint16(before.E), uint16(after.E)},
+		{"H", uint16(before.H), uint16(after.H)},
+		{"L", uint16(before.L), uint16(after.L)},
+		{"SP", before.SP, after.SP},
+		{"PC", before.PC, after.PC},
+		{"IR", uint16(before.IR), uint16(after.IR)},
+		{"Z", uint16(before.Z), uint16(after.Z)},
+		{"W", uint16(before.W), uint16(after.W)},
+		{"IME", boolBit(before.IME), boolBit(after.IME)},
+		{"IE", uint16(before.IE), uint16(after.IE)},
+		{"IF", uint16(before.IF), uint16(after.IF)},
+	} {
+		if r.old != r.new {
+			deltas = append(deltas, RegisterDelta{Reg: r.name, Old: r.old, New: r.new})
+		}
+	}
+	return deltas
+}
+
+func boolBit(b bool) uint16 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// CycleObserver is given a record of every cycle run by a Core, along with
+// the state after it.
+type CycleObserver interface {
+	ObserveCycle(rec CycleRecord, s State) error
+}
+
+// CycleFormat is the output format of a CycleTracer.
+type CycleFormat int
+
+const (
+	CycleText CycleFormat = iota // a text table
+	CycleJSON                    // JSON lines
+)
+
+// CycleTracer writes a record of every M-cycle run, showing what each unit of
+// the CPU did during it.
+type CycleTracer struct {
+	Format CycleFormat
+
+	w      io.Writer
+	header bool
+}
+
+// NewCycleTracer returns a cycle tracer writing a text table to w.
+func NewCycleTracer(w io.Writer) *CycleTracer {
+	return &CycleTracer{w: w}
+}
+
+// ObserveCycle traces the cycle.
+func (t *CycleTracer) ObserveCycle(rec CycleRecord, _ State) error {
+	return t.Trace(rec)
+}
+
+// Trace writes the record of a cycle.
+func (t *CycleTracer) Trace(rec CycleRecord) error {
+	if t.Format == CycleJSON {
+		return json.NewEncoder(t.w).Encode(rec)
+	}
+
+	if !t.header {
+		t.header = true
+		if _, err := fmt.Fprintf(t.w, "%6s %2s  %-12s %-5s %-4s %-11s %-8s %-19s %-16s %s\n",
+			"CYCLE", "S", "ADDR", "BUS", "DATA", "DATA OP", "IDU", "ALU", "MISC", "CHANGES"); err != nil {
+			return err
+		}
+	}
+
+	if rec.Idle {
+		_, err := fmt.Fprintf(t.w, "%6d %2s  (idle)\n", rec.N, "")
+		return err
+	}
+
+	bus, data := "-", "-"
+	if rec.Read || rec.Write {
+		bus, data = "read", fmt.Sprintf("$%02X", rec.Data)
+		if rec.Write {
+			bus = "write"
+		}
+	}
+	var changes []string
+	for _, d := range rec.Deltas {
+		changes = append(changes, d.String())
+	}
+	c := rec.Cycle
+	line := fmt.Sprintf("%6d %2d  %-12s %-5s %-4s %-11s %-8s %-19s %-16s %s",
+		rec.N, rec.S, fmt.Sprintf("%s $%04X", c.Addr, rec.Addr), bus, data,
+		orDash(opName(c.Data)), orDash(opName(c.IDU)), orDash(opName(c.ALU)), orDash(opName(c.Misc)),
+		strings.Join(changes, " "))
+	_, err := fmt.Fprintln(t.w, strings.TrimRight(line, " "))
+	return err
+}
+
+// newCycleRecord builds the record of the nth cycle run.
+func newCycleRecord(n int, before, after State, info cycleInfo) CycleRecord {
+	return CycleRecord{
+		N:      n,
+		S:      before.S,
+		Idle:   info.Idle,
+		Cycle:  info.Cycle,
+		Addr:   info.Addr,
+		Data:   info.Access.Data,
+		Read:   info.Accessed && !info.Access.Write,
+		Write:  info.Accessed && info.Access.Write,
+		Deltas: registerDeltas(before, after),
+	}
+}
+
+func (d RegisterDelta) String() string {
+	if d.Reg == "SP" || d.Reg == "PC" {
+		return fmt.Sprintf("%s $%04X→$%04X", d.Reg, d.Old, d.New)
+	}
+	return fmt.Sprintf("%s $%02X→$%02X", d.Reg, d.Old, d.New)
+}
+
+// opName returns the name of a unit's op, or "" for none.
+func opName[T interface {
+	~uint8
+	fmt.Stringer
+}](op T) string {
+	if op == 0 {
+		return ""
+	}
+	return op.String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// MarshalJSON writes the ops by name.
+func (c Cycle) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Addr  string `json:"addr"`
+		Data  string `json:"data,omitempty"`
+		IDU   string `json:"idu,omitempty"`
+		ALU   string `json:"alu,omitempty"`
+		Misc  string `json:"misc,omitempty"`
+		Fetch bool   `json:"fetch,omitempty"`
+	}{c.Addr.String(), opName(c.Data), opName(c.IDU), opName(c.ALU), opName(c.Misc), c.Fetch})
+}
@@ -0,0 +1,68 @@
+package cpu
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCycleTracer(t *testing.T) {
+	program := []uint8{
+		0x3E, 0x42, // ld a, $42
+		0xE0, 0x80, // ldh ($80), a
+		0x76, // halt
+	}
+	run := func(format CycleFormat) string {
+		var sb strings.Builder
+		var bus flatBus
+		copy(bus[0x100:], program)
+		c := NewCore(&bus)
+		c.IR, c.S, c.PC, c.IF = bus[0x100], 0, 0x0101, 0
+		tracer := NewCycleTracer(&sb)
+		tracer.Format = format
+		c.CycleObserver = tracer
+		_, err := c.RunCycles(2 + 3 + 1 + 1)
+		assert.NoError(t, err)
+		return sb.String()
+	}
+
+	t.Run("text", func(t *testing.T) {
+		lines := strings.Split(run(CycleText), "\n")
+		assert.Equal(t, []string{
+			" CYCLE  S  ADDR         BUS   DATA DATA OP     IDU      ALU                 MISC             CHANGES",
+			"     0  0  PC $0101     read  $42  Z ←         ++       -                   -                PC $0101→$0102 Z $00→$42",
+			"     1  1  PC $0102     read  $E0  IR ←        IncSetPC r ← Z               -                A $01→$42 PC $0102→$0103 IR $3E→$E0",
+			"     2  0  PC $0103     read  $80  Z ←         ++       -                   -                PC $0103→$0104 Z $42→$80",
+			"     3  1  0xFF00 + Z $FF80 write $42  ← A         -        -                   -",
+			"     4  2  PC $0104     read  $76  IR ←        IncSetPC -                   -                PC $0104→$0105 IR $E0→$76",
+			"     5  0  PC $0105     read  $00  IR ←        IncSetPC -                   HALT             PC $0105→$0106 IR $76→$00",
+			"     6     (idle)",
+			"",
+		}, lines)
+	})
+
+	t.Run("json lines", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSpace(run(CycleJSON)), "\n")
+		assert.Len(t, lines, 7)
+
+		var rec struct {
+			N     int
+			S     int
+			Cycle map[string]any
+			Addr  uint16 `json:"address"`
+			Data  uint8
+			Write bool
+		}
+		assert.NoError(t, json.Unmarshal([]byte(lines[3]), &rec))
+		assert.Equal(t, 3, rec.N)
+		assert.Equal(t, 1, rec.S)
+		assert.Equal(t, map[string]any{"addr": "0xFF00 + Z", "data": "← A"}, rec.Cycle)
+		assert.EqualValues(t, 0xFF80, rec.Addr)
+		assert.EqualValues(t, 0x42, rec.Data)
+		assert.True(t, rec.Write)
+
+		assert.JSONEq(t, `{"n": 6, "s": 0, "idle": true, "cycle": {"addr": "0x0000"}, "address": 0, "data": 0}`, lines[6])
+	})
+}
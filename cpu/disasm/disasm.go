@@ -0,0 +1,231 @@
+// Package disasm disassembles SM83 machine code, using the CPU's opcode definitions.
+//
+// Mnemonics are taken from the defs. Their operand placeholders are resolved
+// against the opcode bits matched by the wildcards of the def's code mask (in
+// order, most significant bits first) and the immediate bytes that follow the opcode:
+//
+//	r, r'  register, from a 3-bit field
+//	rr     register pair, from a 2-bit field (AF instead of SP for stack ops)
+//	cc     condition, from a 2-bit field
+//	b      bit number, from a 3-bit field
+//	n      restart vector from a 3-bit field if one remains, otherwise an immediate byte
+//	nn     immediate word
+//	e      signed immediate byte, resolved to a target address for jumps
+package disasm
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/wmarshpersonal/gogeebee/cpu"
+	"github.com/wmarshpersonal/gogeebee/cpu/opdef"
+)
+
+// ErrTruncated is returned when the code ends before the instruction does.
+var ErrTruncated = errors.New("truncated instruction")
+
+// UndefinedOpcodeError is returned for an opcode with no def.
+type UndefinedOpcodeError struct {
+	Prefix bool
+	Opcode uint8
+}
+
+func (e UndefinedOpcodeError) Error() string {
+	var prefix string
+	if e.Prefix {
+		prefix = "CB"
+	}
+	return fmt.Sprintf("undefined opcode $%s%02X", prefix, e.Opcode)
+}
+
+// Instruction is a disassembled instruction.
+type Instruction struct {
+	Addr  uint16  // address of the instruction
+	Bytes []uint8 // encoding, including any prefix & immediates
+	Text  string  // assembly text, e.g. "JP NZ, $1234"
+}
+
+func (i Instruction) String() string {
+	return i.Text
+}
+
+// Disassembler decodes instructions using a set of opcode defs.
+type Disassembler struct {
+	ops, opsCB [0x100]*entry
+}
+
+type entry struct {
+	def    opdef.Def
+	code   opdef.Code
+	length int  // bytes, including any prefix
+	rrstk  bool // rr selects from the stack register pairs
+}
+
+// New returns a disassembler for the opcode defs in fsys.
+func New(fsys fs.FS) (*Disassembler, error) {
+	defs, err := opdef.Parse(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	var d Disassembler
+	for _, def := range defs {
+		length := 1
+		if def.Prefix {
+			length++
+		}
+		var rrstk bool
+		for _, c := range def.Cycles {
+			// immediates are read from PC, incrementing it
+			if c.Addr == "PC" && c.IDU == "++" && !c.Ftch {
+				length++
+			}
+			rrstk = rrstk || strings.Contains(c.Data+c.Misc, "rrstk")
+		}
+
+		table := &d.ops
+		if def.Prefix {
+			table = &d.opsCB
+		}
+		for _, code := range def.Codes {
+			table[code.Value] = &entry{def: def, code: code, length: length, rrstk: rrstk}
+		}
+	}
+
+	return &d, nil
+}
+
+var defaultDisassembler = sync.OnceValues(func() (*Disassembler, error) {
+	return New(cpu.DefFS())
+})
+
+// Disassemble decodes the instruction at the start of code, which is located at addr,
+// using the CPU's opcode defs.
+func Disassemble(addr uint16, code []uint8) (Instruction, error) {
+	d, err := defaultDisassembler()
+	if err != nil {
+		return Instruction{}, err
+	}
+	return d.Disassemble(addr, code)
+}
+
+// Disassemble decodes the instruction at the start of code, which is located at addr.
+func (d *Disassembler) Disassemble(addr uint16, code []uint8) (Instruction, error) {
+	if len(code) == 0 {
+		return Instruction{}, ErrTruncated
+	}
+
+	e, prefix := d.ops[code[0]], false
+	if code[0] == 0xCB {
+		if len(code) < 2 {
+			return Instruction{}, ErrTruncated
+		}
+		e, prefix = d.opsCB[code[1]], true
+	}
+	if e == nil {
+		op := code[0]
+		if prefix {
+			op = code[1]
+		}
+		return Instruction{}, UndefinedOpcodeError{Prefix: prefix, Opcode: op}
+	}
+	if len(code) < e.length {
+		return Instruction{}, ErrTruncated
+	}
+
+	return Instruction{
+		Addr:  addr,
+		Bytes: code[:e.length:e.length],
+		Text:  e.text(addr, code[:e.length]),
+	}, nil
+}
+
+var placeholder = regexp.MustCompile(`\br'|\b(?:rr|cc|nn|r|b|n|e)\b`)
+
+var (
+	r8Names   = [8]string{"b", "c", "d", "e", "h", "l", "(hl)", "a"}
+	r16Names  = [4]string{"bc", "de", "hl", "sp"}
+	stkNames  = [4]string{"bc", "de", "hl", "af"}
+	condNames = [4]string{"nz", "z", "nc", "c"}
+)
+
+// text renders the instruction's assembly text.
+func (e *entry) text(addr uint16, code []uint8) string {
+	op, operands, _ := strings.Cut(e.def.Mnemonic, " ")
+	fields := fieldReader{value: e.code.Value, wildcard: e.code.Wildcard, pos: 7}
+	imm := code[1:]
+	if e.def.Prefix {
+		imm = code[2:]
+	}
+
+	text := placeholder.ReplaceAllStringFunc(operands, func(p string) string {
+		switch p {
+		case "r", "r'":
+			return r8Names[fields.take(3)]
+		case "rr":
+			if e.rrstk {
+				return stkNames[fields.take(2)]
+			}
+			return r16Names[fields.take(2)]
+		case "cc":
+			return condNames[fields.take(2)]
+		case "b":
+			return fmt.Sprint(fields.take(3))
+		case "n":
+			if fields.remaining() > 0 {
+				return fmt.Sprintf("$%02X", fields.take(3)<<3)
+			}
+			return fmt.Sprintf("$%02X", imm[0])
+		case "nn":
+			return fmt.Sprintf("$%04X", uint16(imm[1])<<8|uint16(imm[0]))
+		case "e":
+			if op == "jr" {
+				return fmt.Sprintf("$%04X", addr+uint16(len(code))+uint16(int8(imm[0])))
+			}
+			if int8(imm[0]) < 0 {
+				return fmt.Sprintf("-$%02X", -int(int8(imm[0])))
+			}
+			return fmt.Sprintf("$%02X", imm[0])
+		}
+		return p
+	})
+	text = strings.ReplaceAll(text, "+-", "-")
+
+	if text == "" {
+		return strings.ToUpper(op)
+	}
+	return strings.ToUpper(op + " " + text)
+}
+
+// fieldReader reads the wildcard bits of an opcode as fields, most significant first.
+type fieldReader struct {
+	value, wildcard uint8
+	pos             int // next bit
+}
+
+// take reads the next n-bit field.
+func (f *fieldReader) take(n int) uint8 {
+	var v uint8
+	for ; n > 0 && f.pos >= 0; f.pos-- {
+		if f.wildcard&(1<<f.pos) != 0 {
+			v = v<<1 | (f.value>>f.pos)&1
+			n--
+		}
+	}
+	return v
+}
+
+// remaining returns the number of wildcard bits left to read.
+func (f *fieldReader) remaining() int {
+	var n int
+	for pos := f.pos; pos >= 0; pos-- {
+		if f.wildcard&(1<<pos) != 0 {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,78 @@
+package disasm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisassemble(t *testing.T) {
+	tests := []struct {
+		code []uint8
+		want string
+	}{
+		{[]uint8{0x00}, "NOP"},
+		{[]uint8{0xC2, 0x34, 0x12}, "JP NZ, $1234"},
+		{[]uint8{0xDA, 0x34, 0x12}, "JP C, $1234"},
+		{[]uint8{0x22}, "LD (HL+), A"},
+		{[]uint8{0xCB, 0x5B}, "BIT 3, E"},
+		{[]uint8{0xCB, 0x7E}, "BIT 7, (HL)"},
+		{[]uint8{0xCB, 0x37}, "SWAP A"},
+		{[]uint8{0x41}, "LD B, C"},
+		{[]uint8{0x7E}, "LD A, (HL)"},
+		{[]uint8{0x70}, "LD (HL), B"},
+		{[]uint8{0x36, 0x99}, "LD (HL), $99"},
+		{[]uint8{0x0E, 0x12}, "LD C, $12"},
+		{[]uint8{0x3C}, "INC A"},
+		{[]uint8{0x35}, "DEC (HL)"},
+		{[]uint8{0x88}, "ADC B"},
+		{[]uint8{0xFE, 0x90}, "CP $90"},
+		{[]uint8{0x21, 0xCD, 0xAB}, "LD HL, $ABCD"},
+		{[]uint8{0x39}, "ADD HL, SP"},
+		{[]uint8{0xF5}, "PUSH AF"},
+		{[]uint8{0xD1}, "POP DE"},
+		{[]uint8{0xEF}, "RST $28"},
+		{[]uint8{0x18, 0xFE}, "JR $0100"},
+		{[]uint8{0x20, 0x05}, "JR NZ, $0107"},
+		{[]uint8{0xF8, 0xFD}, "LD HL, SP-$03"},
+		{[]uint8{0xE8, 0x05}, "ADD SP, $05"},
+		{[]uint8{0xE0, 0x44}, "LDH ($44), A"},
+		{[]uint8{0xF2}, "LDH A, (C)"},
+		{[]uint8{0xEA, 0x00, 0xC0}, "LD ($C000), A"},
+		{[]uint8{0x08, 0x00, 0xC0}, "LD ($C000), SP"},
+		{[]uint8{0xC8}, "RET Z"},
+		{[]uint8{0xD4, 0x00, 0x40}, "CALL NC, $4000"},
+		{[]uint8{0x10, 0x00}, "STOP"},
+		{[]uint8{0xD3}, "ILLEGAL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got, err := Disassemble(0x100, tt.code)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got.Text)
+				assert.Equal(t, tt.code, got.Bytes, "length")
+			}
+		})
+	}
+}
+
+func TestDisassemble_AllOpcodes(t *testing.T) {
+	for op := range 0x100 {
+		code := []uint8{uint8(op), 0x00, 0x00}
+		if op == 0xCB {
+			continue
+		}
+		_, err := Disassemble(0, code)
+		assert.NoErrorf(t, err, "$%02X", op)
+
+		_, err = Disassemble(0, []uint8{0xCB, uint8(op)})
+		assert.NoErrorf(t, err, "$CB%02X", op)
+	}
+}
+
+func TestDisassemble_Truncated(t *testing.T) {
+	for _, code := range [][]uint8{{}, {0xCB}, {0xC3, 0x00}, {0x3E}} {
+		_, err := Disassemble(0, code)
+		assert.ErrorIs(t, err, ErrTruncated)
+	}
+}
@@ -0,0 +1,79 @@
+package cpu
+
+import "fmt"
+
+// Fault locates the point of execution at which an error occurred.
+type Fault struct {
+	PC uint16 // program counter
+	IR uint8  // instruction register
+	CB bool   // IR holds a CB-prefixed opcode
+	S  int    // cycle-step in instruction
+}
+
+func (f Fault) String() string {
+	var prefix string
+	if f.CB {
+		prefix = "CB"
+	}
+	return fmt.Sprintf("PC=$%04X IR=$%s%02X S=%d", f.PC, prefix, f.IR, f.S)
+}
+
+// InvalidOpcodeError is the error for an instruction register value with no opcode definition.
+type InvalidOpcodeError struct {
+	Fault
+}
+
+func (e InvalidOpcodeError) Error() string {
+	return fmt.Sprintf("invalid opcode (%v)", e.Fault)
+}
+
+// InvalidRegisterError is the error for an opcode param that doesn't select a valid register.
+type InvalidRegisterError struct {
+	Fault
+	Register uint8 // the invalid register param
+}
+
+func (e InvalidRegisterError) Error() string {
+	return fmt.Sprintf("invalid register %d (%v)", e.Register, e.Fault)
+}
+
+// InvalidOpError is the error for a cycle containing an operation its unit can't perform.
+type InvalidOpError struct {
+	Fault
+	Op fmt.Stringer // the invalid operation
+}
+
+func (e InvalidOpError) Error() string {
+	return fmt.Sprintf("invalid %T %v (%v)", e.Op, e.Op, e.Fault)
+}
+
+// InvalidStateError is the error for a cycle that can't be run from the current state.
+type InvalidStateError struct {
+	Fault
+	Reason string
+}
+
+func (e InvalidStateError) Error() string {
+	return fmt.Sprintf("invalid state: %s (%v)", e.Reason, e.Fault)
+}
+
+// faultError converts a value recovered from a CPU panic into its error, located at f.
+// Values that weren't raised by the CPU are re-panicked.
+func faultError(r any, f Fault) error {
+	switch err := r.(type) {
+	case InvalidOpcodeError:
+		err.Fault = f
+		return err
+	case InvalidRegisterError:
+		err.Fault = f
+		return err
+	case InvalidOpError:
+		err.Fault = f
+		return err
+	case InvalidStateError:
+		err.Fault = f
+		return err
+	default:
+		panic(r)
+	}
+}
@@ -0,0 +1,372 @@
+package cpu
+
+// The threaded interpreter runs pre-decoded opcodes on a *State in place,
+// instead of passing the State by value through each unit's Do. Every opcode
+// of an instruction set is compiled once, with its register fields resolved,
+// into a fastCycle per cycle. Ops without a fast version fall back to their Do,
+// so the interpreter runs cycle-for-cycle identically to the pipeline.
+
+// fastCycle is a cycle pre-decoded for an opcode.
+type fastCycle struct {
+	cycle Cycle  // the cycle as executed, including any fetch
+	fault string // the cycle can't be started (see StartCycle)
+
+	alu   func(s *State)
+	addr  func(s *State) uint16
+	read  func(s *State, v uint8) // data bus read...
+	write func(s *State) uint8    // ...or write...
+	data  func(s *State)          // ...or other data op
+	idu   func(s *State)
+	misc  func(s *State)
+}
+
+type fastOpcode []fastCycle
+
+// fastTables are the compiled opcodes of an instruction set.
+type fastTables struct {
+	ops, opsCB [0x100]fastOpcode
+	interrupt  fastOpcode
+	prefix     fastCycle
+}
+
+// fast returns the instruction set's compiled opcodes, compiling them on first use.
+func (is *InstructionSet) fast() *fastTables {
+	is.fastOnce.Do(func() {
+		t := &fastTables{}
+		for code := range 0x100 {
+			t.ops[code] = compileOpcode(is.ops[code], uint8(code))
+			t.opsCB[code] = compileOpcode(is.opsCB[code], uint8(code))
+		}
+		t.interrupt = compileOpcode(interruptOpcode, 0)
+		t.prefix = compileCycle(Cycle{Addr: AddrPC, Data: ReadIR, IDU: IncSetPC, Misc: Set_CB}, 0xCB)
+		is.fastTables = t
+	})
+	return is.fastTables
+}
+
+func compileOpcode(op Opcode, opcode uint8) fastOpcode {
+	if op == nil {
+		return nil
+	}
+	fop := make(fastOpcode, len(op))
+	for i, cycle := range op {
+		fop[i] = compileCycle(cycle, opcode)
+	}
+	return fop
+}
+
+func compileCycle(cycle Cycle, opcode uint8) fastCycle {
+	if cycle.Fetch {
+		if cycle.Data != 0 || cycle.IDU != 0 {
+			return fastCycle{cycle: cycle, fault: "not enough free ops for fetch"}
+		}
+		cycle.Data = ReadIR
+		cycle.IDU = IncSetPC
+	}
+
+	fc := fastCycle{
+		cycle: cycle,
+		alu:   compileALU(cycle.ALU, opcode),
+		addr:  compileAddr(cycle.Addr),
+		idu:   compileIDU(cycle.IDU, cycle.Addr),
+		misc:  compileMisc(cycle.Misc, opcode),
+	}
+	switch op := cycle.Data; {
+	case op.RD():
+		fc.read = compileRead(op)
+	case op == W_Equals_ALU:
+		fc.data = func(s *State) { s.W = s.ALUResult }
+	case op != 0:
+		fc.write = compileWrite(op, opcode)
+	}
+	return fc
+}
+
+func compileAddr(op AddrSelector) func(s *State) uint16 {
+	switch op {
+	case AddrZero:
+		return func(s *State) uint16 { return 0x0000 }
+	case AddrHI_plus_C:
+		return func(s *State) uint16 { return 0xFF00 + uint16(s.C) }
+	case AddrHI_plus_Z:
+		return func(s *State) uint16 { return 0xFF00 + uint16(s.Z) }
+	case AddrPC:
+		return func(s *State) uint16 { return s.PC }
+	case AddrSP:
+		return func(s *State) uint16 { return s.SP }
+	case AddrHL:
+		return func(s *State) uint16 { return mk16(s.H, s.L) }
+	default:
+		return func(s *State) uint16 { return op.Do(*s) }
+	}
+}
+
+func compileRead(op DataOp) func(s *State, v uint8) {
+	switch op {
+	case ReadIR:
+		return func(s *State, v uint8) { s.IR = v }
+	case ReadZ:
+		return func(s *State, v uint8) { s.Z = v }
+	default: // ReadW
+		return func(s *State, v uint8) { s.W = v }
+	}
+}
+
+func compileWrite(op DataOp, opcode uint8) func(s *State) uint8 {
+	switch op {
+	case WriteZ:
+		return func(s *State) uint8 { return s.Z }
+	case WriteA:
+		return func(s *State) uint8 { return s.A }
+	case WriteALU:
+		return func(s *State) uint8 { return s.ALUResult }
+	case WritePCL:
+		return func(s *State) uint8 { return lo(s.PC) }
+	case WritePCH:
+		return func(s *State) uint8 { return hi(s.PC) }
+	case WriteR8:
+		if r, ok := r8(opcode & 0b111); ok {
+			return func(s *State) uint8 { return *reg8(s, r) }
+		}
+	}
+	return func(s *State) uint8 {
+		_, v := op.WR(*s, opcode)
+		return v
+	}
+}
+
+func compileIDU(op IDUOp, addr AddrSelector) func(s *State) {
+	if op == 0 {
+		return nil
+	}
+	if addr == AddrPC {
+		switch op {
+		case Inc, IncSetPC:
+			return func(s *State) { s.PC++ }
+		case Dec:
+			return func(s *State) { s.PC-- }
+		}
+	}
+	if addr == AddrSP {
+		switch op {
+		case Inc:
+			return func(s *State) { s.SP++ }
+		case Dec:
+			return func(s *State) { s.SP-- }
+		}
+	}
+	return func(s *State) { *s = op.Do(*s, addr) }
+}
+
+func compileALU(op ALUOp, opcode uint8) func(s *State) {
+	x, xok := r8((opcode >> 3) & 0b111) // register fields
+	y, yok := r8(opcode & 0b111)
+	b := int((opcode >> 3) & 0b111) // bit number
+
+	switch {
+	case op == 0:
+		return nil
+	case op == LD_r_r && xok && yok:
+		return func(s *State) { *reg8(s, x) = *reg8(s, y) }
+	case op == LD_A_Z:
+		return func(s *State) { s.A = s.Z }
+	case op == LD_r_Z && xok:
+		return func(s *State) { *reg8(s, x) = s.Z }
+	case op == INC_r && xok:
+		return func(s *State) { r := reg8(s, x); *r, s.F = aluINC(*r, s.F) }
+	case op == DEC_r && xok:
+		return func(s *State) { r := reg8(s, x); *r, s.F = aluDEC(*r, s.F) }
+	case op == INC_Z:
+		return func(s *State) { s.Z, s.F = aluINC(s.Z, s.F) }
+	case op == DEC_Z:
+		return func(s *State) { s.Z, s.F = aluDEC(s.Z, s.F) }
+	case op == ADD_Z:
+		return func(s *State) { s.A, s.F = aluADD(s.A, s.Z, s.F) }
+	case op == ADC_Z:
+		return func(s *State) { s.A, s.F = aluADC(s.A, s.Z, s.F) }
+	case op == SUB_Z:
+		return func(s *State) { s.A, s.F = aluSUB(s.A, s.Z, s.F) }
+	case op == SBC_Z:
+		return func(s *State) { s.A, s.F = aluSBC(s.A, s.Z, s.F) }
+	case op == AND_Z:
+		return func(s *State) { s.A, s.F = aluAND(s.A, s.Z) }
+	case op == XOR_Z:
+		return func(s *State) { s.A, s.F = aluXOR(s.A, s.Z) }
+	case op == OR_Z:
+		return func(s *State) { s.A, s.F = aluOR(s.A, s.Z) }
+	case op == CP_Z:
+		return func(s *State) { _, s.F = aluSUB(s.A, s.Z, s.F) }
+	case op == ADD_r && yok:
+		return func(s *State) { s.A, s.F = aluADD(s.A, *reg8(s, y), s.F) }
+	case op == ADC_r && yok:
+		return func(s *State) { s.A, s.F = aluADC(s.A, *reg8(s, y), s.F) }
+	case op == SUB_r && yok:
+		return func(s *State) { s.A, s.F = aluSUB(s.A, *reg8(s, y), s.F) }
+	case op == SBC_r && yok:
+		return func(s *State) { s.A, s.F = aluSBC(s.A, *reg8(s, y), s.F) }
+	case op == AND_r && yok:
+		return func(s *State) { s.A, s.F = aluAND(s.A, *reg8(s, y)) }
+	case op == XOR_r && yok:
+		return func(s *State) { s.A, s.F = aluXOR(s.A, *reg8(s, y)) }
+	case op == OR_r && yok:
+		return func(s *State) { s.A, s.F = aluOR(s.A, *reg8(s, y)) }
+	case op == CP_r && yok:
+		return func(s *State) { _, s.F = aluSUB(s.A, *reg8(s, y), s.F) }
+	case op == BIT_Z:
+		return func(s *State) { s.F = aluBIT(b, s.Z, s.F) }
+	case op == BIT_r && yok:
+		return func(s *State) { s.F = aluBIT(b, *reg8(s, y), s.F) }
+	case op == RES_r && yok:
+		return func(s *State) { r := reg8(s, y); *r = aluRES(b, *r) }
+	case op == SET_r && yok:
+		return func(s *State) { r := reg8(s, y); *r = aluSET(b, *r) }
+	case op == Res_Z_Equals_PCL_Plus_ZSigned:
+		return func(s *State) {
+			res16 := uint16(int(s.PC) + int(int8(s.Z)))
+			s.Z, s.ALUResult = lo(res16), hi(res16)
+		}
+	case op == W_Equals_res:
+		return func(s *State) { s.W = s.ALUResult }
+	default:
+		return func(s *State) { *s = op.Do(*s, opcode) }
+	}
+}
+
+func compileMisc(op MiscOp, opcode uint8) func(s *State) {
+	switch op {
+	case 0:
+		return nil
+	case PC_Equals_WZ:
+		return func(s *State) { s.PC = mk16(s.W, s.Z) }
+	case SP_Equals_WZ:
+		return func(s *State) { s.SP = mk16(s.W, s.Z) }
+	case Set_CB:
+		return func(s *State) { s.CB = true }
+	case Cond:
+		cc := Condition((opcode >> 3) & 0b11)
+		return func(s *State) {
+			if cc.Test(s.F) {
+				s.S++
+			}
+		}
+	default:
+		return func(s *State) { *s = op.Do(*s, opcode) }
+	}
+}
+
+// r8 converts a register field of an opcode, reporting whether it selects a register.
+func r8(v uint8) (R8, bool) {
+	return R8(v), v != 6 && v <= 7
+}
+
+// reg8 returns a pointer to the 8-bit register r, which must be valid.
+func reg8(s *State, r R8) *uint8 {
+	switch r {
+	case B:
+		return &s.B
+	case C:
+		return &s.C
+	case D:
+		return &s.D
+	case E:
+		return &s.E
+	case H:
+		return &s.H
+	case L:
+		return &s.L
+	default:
+		return &s.A
+	}
+}
+
+// fastStep is step for the threaded interpreter, running a single M-cycle on
+// the state in place. Errors are raised as panics, as by the pipeline.
+func (is *InstructionSet) fastStep(s *State, bus Bus) (info cycleInfo) {
+	t := is.fast()
+
+	// NextCycle
+	beginCycle(s)
+	if s.Idle() {
+		bus.Tick()
+		fastInterrupts(s, bus)
+		return cycleInfo{Idle: true}
+	}
+
+	var fc *fastCycle
+	switch {
+	case s.Interrupting:
+		fc = &t.interrupt[s.S]
+	case s.S == 0 && s.IR == 0xCB:
+		fc = &t.prefix
+	default:
+		op, i := t.ops[s.IR], s.S
+		if s.CB {
+			op, i = t.opsCB[s.IR], i-1
+		}
+		if op == nil {
+			panic(InvalidOpcodeError{})
+		}
+		if i >= len(op) {
+			panic(InvalidStateError{Reason: "cycle-step out of range"})
+		}
+		fc = &op[i]
+	}
+
+	// StartCycle
+	if fc.fault != "" {
+		panic(InvalidStateError{Reason: fc.fault})
+	}
+	s.S++
+	if fc.cycle.Fetch {
+		s.S = 0
+		s.CB = false
+		s.Interrupting = false
+	}
+	if fc.alu != nil {
+		fc.alu(s)
+	}
+
+	// memory access
+	addr := fc.addr(s)
+	info.Cycle, info.Addr = fc.cycle, addr
+	var data uint8
+	switch {
+	case fc.read != nil:
+		data = busRead(s, bus, addr)
+		info.Access, info.Accessed = Access{Addr: addr, Data: data}, true
+	case fc.write != nil:
+		v := fc.write(s)
+		busWrite(s, bus, addr, v)
+		info.Access, info.Accessed = Access{Addr: addr, Data: v, Write: true}, true
+	default:
+		bus.Tick()
+	}
+
+	// FinishCycle
+	imePending := s.IMEPending
+	if fc.read != nil {
+		fc.read(s, data)
+	} else if fc.data != nil {
+		fc.data(s)
+	}
+	if fc.idu != nil {
+		fc.idu(s)
+	}
+	if fc.misc != nil {
+		fc.misc(s)
+	}
+	if fc.cycle.Fetch && imePending && s.IMEPending {
+		s.IME = true
+		s.IMEPending = false
+	}
+
+	fastInterrupts(s, bus)
+	return info
+}
+
+// fastInterrupts is interrupts, in place.
+func fastInterrupts(s *State, bus Bus) {
+	if src, ok := bus.(InterruptSource); ok {
+		s.IF |= src.Interrupts() & 0x1F
+	}
+}
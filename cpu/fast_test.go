@@ -0,0 +1,175 @@
+package cpu
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingBus logs every bus call, and requests random interrupts.
+type recordingBus struct {
+	flatBus
+	log []string
+	rng *rand.Rand
+}
+
+func (b *recordingBus) Read(addr uint16) uint8 {
+	v := b.flatBus.Read(addr)
+	b.log = append(b.log, fmt.Sprintf("R %04X %02X", addr, v))
+	return v
+}
+
+func (b *recordingBus) Write(addr uint16, v uint8) {
+	b.log = append(b.log, fmt.Sprintf("W %04X %02X", addr, v))
+	b.flatBus.Write(addr, v)
+}
+
+func (b *recordingBus) Tick() {
+	b.log = append(b.log, "T")
+}
+
+func (b *recordingBus) Interrupts() uint8 {
+	if b.rng.IntN(64) == 0 {
+		return 1 << b.rng.IntN(5)
+	}
+	return 0
+}
+
+// newRandomCore returns a core in a random state, on a bus filled with random
+// bytes that are mostly legal opcodes.
+func newRandomCore(seed uint64) *Core {
+	rng := rand.New(rand.NewPCG(seed, 0))
+	bus := &recordingBus{rng: rand.New(rand.NewPCG(seed, 1))}
+	for i := range bus.flatBus {
+		v := uint8(rng.Uint32())
+		for slices.Contains([]uint8{0x10, 0xD3, 0xDB, 0xDD, 0xE3, 0xE4, 0xEB, 0xEC, 0xED, 0xF4, 0xFC, 0xFD}, v) {
+			v = uint8(rng.Uint32())
+		}
+		bus.flatBus[i] = v
+	}
+
+	c := NewCore(bus)
+	c.PC = uint16(rng.Uint32())
+	c.IR, c.S = bus.flatBus[c.PC], 0
+	c.PC++
+	c.SP = uint16(rng.Uint32())
+	c.A, c.F = uint8(rng.Uint32()), uint8(rng.Uint32())&0xF0
+	c.B, c.C, c.D, c.E = uint8(rng.Uint32()), uint8(rng.Uint32()), uint8(rng.Uint32()), uint8(rng.Uint32())
+	c.H, c.L = uint8(rng.Uint32()), uint8(rng.Uint32())
+	c.IME = rng.IntN(2) == 0
+	c.IE, c.IF = uint8(rng.Uint32())&0x1F, 0
+	return c
+}
+
+// TestFast checks the threaded interpreter runs identically to the pipeline.
+func TestFast(t *testing.T) {
+	t.Run("random programs", func(t *testing.T) {
+		for seed := range uint64(20) {
+			ref, fast := newRandomCore(seed), newRandomCore(seed)
+			fast.Fast = true
+
+			for cycle := range 5000 {
+				refInfo, refErr := ref.step()
+				fastInfo, fastErr := fast.step()
+				if !assert.Equalf(t, refErr, fastErr, "seed %d cycle %d", seed, cycle) ||
+					!assert.Equalf(t, refInfo, fastInfo, "seed %d cycle %d", seed, cycle) ||
+					!assert.Equalf(t, ref.State, fast.State, "seed %d cycle %d", seed, cycle) {
+					return
+				}
+				if ref.Locked {
+					break
+				}
+			}
+			assert.Equal(t, ref.Bus.(*recordingBus).log, fast.Bus.(*recordingBus).log)
+		}
+	})
+
+	t.Run("every opcode", func(t *testing.T) {
+		for prefix := range 2 {
+			for code := range 0x100 {
+				for seed := range uint64(4) {
+					ref, fast := newRandomCore(seed), newRandomCore(seed)
+					fast.Fast = true
+					for _, c := range []*Core{ref, fast} {
+						mem := &c.Bus.(*recordingBus).flatBus
+						mem[c.PC-1], mem[c.PC] = uint8(code), uint8(code)
+						if prefix == 1 {
+							mem[c.PC-1] = 0xCB
+						}
+						c.IR = mem[c.PC-1]
+					}
+
+					for range 8 {
+						refErr, fastErr := ref.Step(), fast.Step()
+						if !assert.Equal(t, refErr, fastErr) ||
+							!assert.Equalf(t, ref.State, fast.State, "opcode %d/$%02X seed %d", prefix, code, seed) {
+							return
+						}
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		ops, opsCB := operations, operationsCB
+		ops[0x00] = nil
+		is := &InstructionSet{ops: &ops, opsCB: &opsCB, infos: &infos, infosCB: &infosCB}
+
+		var bus flatBus
+		ref, fast := NewCore(&bus), NewCore(&bus)
+		fast.Fast = true
+		for _, c := range []*Core{ref, fast} {
+			c.InstructionSet = is
+			c.IR, c.S, c.PC = 0x00, 0, 0x0101
+		}
+		before := fast.State
+
+		refErr, fastErr := ref.Step(), fast.Step()
+		assert.True(t, errors.As(fastErr, new(InvalidOpcodeError)))
+		assert.Equal(t, refErr, fastErr)
+		assert.Equal(t, before, fast.State)
+	})
+}
+
+// loopProgram copies a page of memory over & over.
+var loopProgram = []uint8{
+	0x21, 0x00, 0xC0, // ld hl, $C000
+	0x11, 0x00, 0xD0, // ld de, $D000
+	0x01, 0x00, 0x01, // ld bc, $0100
+	0x2A,       // ld a, (hl+)
+	0x12,       // ld (de), a
+	0x13,       // inc de
+	0x0B,       // dec bc
+	0x78,       // ld a, b
+	0xB1,       // or c
+	0x20, 0xF8, // jr nz, -8
+	0x18, 0xED, // jr -19
+}
+
+func BenchmarkCore(b *testing.B) {
+	for _, fast := range []bool{false, true} {
+		name := "pipeline"
+		if fast {
+			name = "fast"
+		}
+		b.Run(name, func(b *testing.B) {
+			var bus flatBus
+			copy(bus[0x100:], loopProgram)
+			c := NewCore(&bus)
+			c.IR, c.S, c.PC = bus[0x100], 0, 0x0101
+			c.Fast = fast
+			c.instructionSet().fast() // compile ahead
+
+			// an op is a cycle
+			b.ResetTimer()
+			if _, err := c.RunCycles(b.N); err != nil {
+				b.Fatal(err)
+			}
+		})
+	}
+}
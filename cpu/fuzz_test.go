@@ -0,0 +1,121 @@
+package cpu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fuzzPC is where fuzzed programs start.
+const fuzzPC = 0x0100
+
+// fuzzMemory is memory filled by repeating a program from fuzzPC, with writes overlaid.
+type fuzzMemory struct {
+	program []uint8
+	written map[uint16]uint8
+}
+
+func newFuzzMemory(program []uint8) *fuzzMemory {
+	return &fuzzMemory{program: program, written: map[uint16]uint8{}}
+}
+
+func (m *fuzzMemory) Read(addr uint16) uint8 {
+	if v, ok := m.written[addr]; ok {
+		return v
+	}
+	if len(m.program) == 0 {
+		return 0
+	}
+	return m.program[int(addr-fuzzPC)%len(m.program)]
+}
+
+func (m *fuzzMemory) Write(addr uint16, v uint8) { m.written[addr] = v }
+func (m *fuzzMemory) Tick()                      {}
+
+// fuzzState is the state compared between the CPU & the reference.
+type fuzzState struct {
+	A, F, B, C, D, E, H, L uint8
+	SP, PC                 uint16
+	IME, IMEPending        bool
+	IE, IF                 uint8
+	Halted, Stopped        bool
+	Locked                 bool
+}
+
+// FuzzCPU runs instruction streams from random states through the CPU & the
+// reference interpreter, comparing their registers, flags, memory writes &
+// cycle counts after every instruction.
+func FuzzCPU(f *testing.F) {
+	// every opcode, from a couple of states
+	for op := range 0x100 {
+		f.Add(uint8(0x01), uint8(0xB0), uint8(0x00), uint8(0x13), uint8(0x00), uint8(0xD8), uint8(0xC1), uint8(0x4D), uint16(0xFFFE), false, uint8(0x00),
+			[]byte{uint8(op), 0x34, 0x12, 0xCB, uint8(op), 0x00})
+		f.Add(uint8(0x9A), uint8(0x70), uint8(0xFF), uint8(0x01), uint8(0x80), uint8(0x7F), uint8(0xD0), uint8(0x0F), uint16(0xD00F), true, uint8(0x1F),
+			[]byte{uint8(op), 0xFF, 0x80, 0x27, 0xCB, uint8(op)})
+	}
+
+	f.Fuzz(func(t *testing.T, a, f, b, c, d, e, h, l uint8, sp uint16, ime bool, ie uint8, program []byte) {
+		ref := &refMachine{
+			A: a, F: f & 0xF0, B: b, C: c, D: d, E: e, H: h, L: l,
+			SP: sp, PC: fuzzPC,
+			IME: ime, IE: ie,
+			mem: newFuzzMemory(program),
+		}
+		core := NewCore(newFuzzMemory(program))
+		core.State = State{
+			A: a, F: f & 0xF0, B: b, C: c, D: d, E: e, H: h, L: l,
+			SP: sp, PC: fuzzPC + 1,
+			IME: ime, IE: ie,
+		}
+		core.IR = core.Bus.Read(fuzzPC)
+
+		for i := range 4 {
+			if ref.IF&ref.IE != 0 {
+				// an interrupt would be dispatched, or HALT would exit early
+				return
+			}
+
+			cycles := ref.Step()
+			res, err := core.StepInstruction()
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var writes []Access
+			for _, access := range res.Accesses {
+				if access.Write {
+					writes = append(writes, access)
+				}
+			}
+			got := fuzzState{
+				A: core.A, F: core.F, B: core.B, C: core.C, D: core.D, E: core.E, H: core.H, L: core.L,
+				SP: core.SP, PC: core.PC - 1,
+				IME: core.IME, IMEPending: core.IMEPending,
+				IE: core.IE, IF: core.IF,
+				Halted: core.Halted, Stopped: core.Stopped, Locked: core.Locked,
+			}
+			if core.Stopped {
+				// no opcode was prefetched
+				got.PC = core.PC
+			}
+			want := fuzzState{
+				A: ref.A, F: ref.F, B: ref.B, C: ref.C, D: ref.D, E: ref.E, H: ref.H, L: ref.L,
+				SP: ref.SP, PC: ref.PC,
+				IME: ref.IME, IMEPending: ref.IMEPending,
+				IE: ref.IE, IF: ref.IF,
+				Halted: ref.Halted, Stopped: ref.Stopped, Locked: ref.Locked,
+			}
+
+			if !assert.Equalf(t, want, got, "instruction %d", i) ||
+				!assert.Equalf(t, ref.writes, writes, "instruction %d", i) ||
+				!assert.Equalf(t, cycles, res.Cycles, "instruction %d", i) {
+				return
+			}
+			ref.writes = nil
+
+			if ref.Halted || ref.Stopped || ref.Locked {
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package cpu
+
+import (
+	"slices"
+
+	"github.com/wmarshpersonal/gogeebee/cpu/opdef"
+)
+
+// Flags describes the effect an opcode has on each flag, in Z, N, H, C order.
+type Flags [4]opdef.FlagEffect
+
+func (f Flags) String() string {
+	b := []byte("----")
+	for i, e := range f {
+		switch e {
+		case opdef.Affected:
+			b[i] = "ZNHC"[i]
+		case opdef.Reset:
+			b[i] = '0'
+		case opdef.Set:
+			b[i] = '1'
+		}
+	}
+	return string(b)
+}
+
+// MemoryOperand is a memory location an opcode accesses, other than through PC.
+type MemoryOperand struct {
+	Addr        AddrSelector
+	Read, Write bool
+}
+
+// Info is metadata about an opcode, derived from its definition.
+type Info struct {
+	Mnemonic  string
+	Length    int             // bytes, including any prefix
+	MinCycles int             // M-cycles when a condition is false
+	MaxCycles int             // M-cycles when a condition is true
+	Memory    []MemoryOperand // memory operands, in order of first access
+	Flags     Flags           // flags affected
+}
+
+// OpcodeInfo returns the metadata for an opcode, which is CB-prefixed if prefix is set.
+// The zero Info is returned for opcodes without a definition.
+func OpcodeInfo(prefix bool, code uint8) Info {
+	return defaultInstructionSet.OpcodeInfo(prefix, code)
+}
+
+// loadsF reports whether an opcode loads F from the stack, as the rrstk ← WZ of
+// pop rr does for AF. Every flag is affected, whatever its def's flags.
+func loadsF(code uint8, op Opcode) bool {
+	return slices.ContainsFunc(op, func(c Cycle) bool { return c.Misc == RRstk_Equals_WZ }) &&
+		rrstkToR16((code>>4)&0b11) == AF
+}
+
+// newInfo derives an opcode's metadata from its cycles.
+func newInfo(mnemonic string, prefix bool, flags Flags, op Opcode) Info {
+	info := Info{
+		Mnemonic:  mnemonic,
+		Length:    1,
+		MinCycles: len(op),
+		MaxCycles: len(op),
+		Flags:     flags,
+	}
+
+	if prefix {
+		// the prefix is fetched in a cycle of its own
+		info.Length++
+		info.MinCycles++
+		info.MaxCycles++
+	}
+
+	for i, cycle := range op {
+		// immediates are read (or skipped) from PC
+		if cycle.Addr == AddrPC && cycle.IDU == Inc {
+			info.Length++
+		}
+
+		// COND is followed by the false branch, a single cycle, then the true branch
+		if cycle.Misc == Cond {
+			info.MinCycles = len(op[:i+2])
+			info.MaxCycles = len(op) - 1
+		}
+
+		rd := cycle.Data.RD()
+		wr := cycle.Data != 0 && !rd && cycle.Data != W_Equals_ALU
+		if cycle.Addr != AddrPC && (rd || wr) {
+			j := -1
+			for k, m := range info.Memory {
+				if m.Addr == cycle.Addr {
+					j = k
+				}
+			}
+			if j < 0 {
+				info.Memory = append(info.Memory, MemoryOperand{Addr: cycle.Addr})
+				j = len(info.Memory) - 1
+			}
+			info.Memory[j].Read = info.Memory[j].Read || rd
+			info.Memory[j].Write = info.Memory[j].Write || wr
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,151 @@
+package cpu
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wmarshpersonal/gogeebee/cpu/opdef"
+)
+
+func TestOpcodeInfo(t *testing.T) {
+	tests := []struct {
+		prefix bool
+		code   uint8
+		want   Info
+	}{
+		{false, 0x00, Info{"nop", 1, 1, 1, nil, Flags{}}},
+		{false, 0x10, Info{"stop", 2, 2, 2, nil, Flags{}}},
+		{false, 0xC2, Info{"jp cc, nn", 3, 3, 4, nil, Flags{}}},
+		{false, 0xC4, Info{"call cc, nn", 3, 3, 6, []MemoryOperand{{AddrSP, false, true}}, Flags{}}},
+		{false, 0xC0, Info{"ret cc", 1, 2, 5, []MemoryOperand{{AddrSP, true, false}}, Flags{}}},
+		{false, 0x20, Info{"jr cc, e", 2, 2, 3, nil, Flags{}}},
+		{false, 0x36, Info{"ld (hl), n", 2, 3, 3, []MemoryOperand{{AddrHL, false, true}}, Flags{}}},
+		{false, 0xE2, Info{"ldh (c), a", 1, 2, 2, []MemoryOperand{{AddrHI_plus_C, false, true}}, Flags{}}},
+		{false, 0xFA, Info{"ld a, (nn)", 3, 4, 4, []MemoryOperand{{AddrWZ, true, false}}, Flags{}}},
+		{false, 0x86, Info{"add (hl)", 1, 2, 2, []MemoryOperand{{AddrHL, true, false}},
+			Flags{opdef.Affected, opdef.Reset, opdef.Affected, opdef.Affected}}},
+		{false, 0xE6, Info{"and n", 2, 2, 2, nil,
+			Flags{opdef.Affected, opdef.Reset, opdef.Set, opdef.Reset}}},
+		{false, 0xC1, Info{"pop rr", 1, 3, 3, []MemoryOperand{{AddrSP, true, false}}, Flags{}}},
+		{false, 0xF1, Info{"pop rr", 1, 3, 3, []MemoryOperand{{AddrSP, true, false}}, // pop af
+			Flags{opdef.Affected, opdef.Affected, opdef.Affected, opdef.Affected}}},
+		{true, 0x7E, Info{"bit b, (hl)", 2, 3, 3, []MemoryOperand{{AddrHL, true, false}},
+			Flags{opdef.Affected, opdef.Reset, opdef.Set, opdef.Unchanged}}},
+		{true, 0xC6, Info{"set b, (hl)", 2, 4, 4, []MemoryOperand{{AddrHL, true, true}}, Flags{}}},
+		{true, 0x11, Info{"rl r", 2, 2, 2, nil,
+			Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Affected}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want.Mnemonic, func(t *testing.T) {
+			assert.Equal(t, tt.want, OpcodeInfo(tt.prefix, tt.code))
+		})
+	}
+}
+
+func TestFlags_String(t *testing.T) {
+	assert.Equal(t, "Z0HC", Flags{opdef.Affected, opdef.Reset, opdef.Affected, opdef.Affected}.String())
+	assert.Equal(t, "-11-", Flags{opdef.Unchanged, opdef.Set, opdef.Set, opdef.Unchanged}.String())
+}
+
+// TestOpcodeInfo_Execution runs every opcode from random states, checking that
+// the flags, cycle counts & lengths in its Info match what actually happens.
+func TestOpcodeInfo_Execution(t *testing.T) {
+	const trials = 200
+	rng := rand.New(rand.NewPCG(1, 2))
+	// random picks a random value, favouring the edge cases that set flags
+	random := func() uint8 {
+		if rng.IntN(2) == 0 {
+			return []uint8{0x00, 0x01, 0x0F, 0x10, 0x7F, 0x80, 0xFF}[rng.IntN(7)]
+		}
+		return uint8(rng.Uint32())
+	}
+	var bus flatBus
+	for i := range bus {
+		bus[i] = random()
+	}
+
+	for _, prefix := range []bool{false, true} {
+		for code := range 0x100 {
+			if !prefix && code == 0xCB {
+				continue
+			}
+			info := OpcodeInfo(prefix, uint8(code))
+			name := fmt.Sprintf("%s ($%02X)", info.Mnemonic, code)
+			t.Run(name, func(t *testing.T) {
+				var changed [4]bool
+				var seen [4][2]bool // flag values seen after execution
+				for range trials {
+					const pc = 0x4000
+					for i := range 4 {
+						bus[pc+i] = random()
+					}
+					bus[pc] = uint8(code)
+					if prefix {
+						bus[pc] = 0xCB
+						bus[pc+1] = uint8(code)
+					}
+
+					c := NewCore(&bus)
+					c.IR, c.S, c.PC = bus[pc], 0, pc+1
+					c.IE, c.IF, c.IME = 0, 0, false
+					c.A, c.B, c.C, c.D = random(), random(), random(), random()
+					c.E, c.H, c.L = random(), random(), random()
+					c.F = uint8(rng.Uint32()) & 0xF0
+					c.SP = uint16(rng.Uint32())
+					before := c.State
+
+					res, err := c.StepInstruction()
+					if !assert.NoError(t, err) {
+						return
+					}
+
+					// cycles
+					if !c.Idle() {
+						if !assert.GreaterOrEqual(t, res.Cycles, info.MinCycles) ||
+							!assert.LessOrEqual(t, res.Cycles, info.MaxCycles) {
+							return
+						}
+						// length, if execution continued in sequence
+						if res.Cycles == info.MinCycles && info.MinCycles == info.MaxCycles && c.PC-1 != pc+uint16(info.Length) {
+							switch info.Mnemonic {
+							case "jp nn", "jp hl", "jr e", "call nn", "ret", "reti", "rst n":
+							default:
+								t.Errorf("length %d, but next instruction at $%04X", info.Length, c.PC-1)
+								return
+							}
+						}
+					}
+
+					// flags
+					for i, effect := range info.Flags {
+						mask := FZ >> i
+						switch effect {
+						case opdef.Unchanged:
+							if !assert.Equalf(t, before.F&mask, c.F&mask, "%c should be unchanged", "ZNHC"[i]) {
+								return
+							}
+						case opdef.Reset:
+							if !assert.Zerof(t, c.F&mask, "%c should be reset", "ZNHC"[i]) {
+								return
+							}
+						case opdef.Set:
+							if !assert.Equalf(t, mask, c.F&mask, "%c should be set", "ZNHC"[i]) {
+								return
+							}
+						case opdef.Affected:
+							changed[i] = changed[i] || before.F&mask != c.F&mask
+							seen[i][(c.F&mask)>>(7-i)] = true
+						}
+					}
+				}
+				for i, effect := range info.Flags {
+					if effect == opdef.Affected {
+						assert.Truef(t, changed[i] || seen[i][0] && seen[i][1], "%c should be affected", "ZNHC"[i])
+					}
+				}
+			})
+		}
+	}
+}
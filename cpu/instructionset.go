@@ -0,0 +1,97 @@
+package cpu
+
+import (
+	"fmt"
+	"io/fs"
+	"sync"
+
+	"github.com/wmarshpersonal/gogeebee/cpu/opdef"
+)
+
+// InstructionSet is a set of opcodes the CPU can run, with their metadata.
+// The default set is compiled from the CPU's own defs (see DefFS); others can
+// be loaded at runtime, so variants of the defs can be run side by side.
+type InstructionSet struct {
+	ops, opsCB     *[0x100]Opcode
+	infos, infosCB *[0x100]Info
+
+	fastOnce   sync.Once
+	fastTables *fastTables // compiled opcodes, for the threaded interpreter
+}
+
+var defaultInstructionSet = &InstructionSet{
+	ops:     &operations,
+	opsCB:   &operationsCB,
+	infos:   &infos,
+	infosCB: &infosCB,
+}
+
+// DefaultInstructionSet returns the instruction set built from the CPU's own defs.
+func DefaultInstructionSet() *InstructionSet {
+	return defaultInstructionSet
+}
+
+// LoadInstructionSet builds an instruction set from the YAML opcode defs in the root of fsys.
+// Defs using micro-ops the CPU doesn't have are an error, located by file & line.
+func LoadInstructionSet(fsys fs.FS) (*InstructionSet, error) {
+	defs, err := opdef.Parse(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	is := &InstructionSet{
+		ops:     new([0x100]Opcode),
+		opsCB:   new([0x100]Opcode),
+		infos:   new([0x100]Info),
+		infosCB: new([0x100]Info),
+	}
+	for _, def := range defs {
+		op := make(Opcode, 0, len(def.Cycles))
+		for _, c := range def.Cycles {
+			var cycle Cycle
+			for _, err := range []error{
+				OpByName(&cycle.Addr, c.Addr),
+				OpByName(&cycle.Data, c.Data),
+				OpByName(&cycle.IDU, c.IDU),
+				OpByName(&cycle.ALU, c.ALU),
+				OpByName(&cycle.Misc, c.Misc),
+			} {
+				if err != nil {
+					return nil, fmt.Errorf("%s:%d: def %q: %w", def.File, c.Line, def.Mnemonic, err)
+				}
+			}
+			cycle.Fetch = c.Ftch
+			op = append(op, cycle)
+		}
+
+		flags, err := opdef.ParseFlags(def.Flags)
+		if err != nil {
+			return nil, fmt.Errorf("%s: def %q: %w", def.Pos(), def.Mnemonic, err)
+		}
+		info := newInfo(def.Mnemonic, def.Prefix, flags, op)
+
+		ops, infos := is.ops, is.infos
+		if def.Prefix {
+			ops, infos = is.opsCB, is.infosCB
+		}
+		for _, code := range def.Codes {
+			ops[code.Value] = op
+			infos[code.Value] = info
+		}
+	}
+
+	return is, nil
+}
+
+// OpcodeInfo returns the metadata for an opcode of the set, which is CB-prefixed if prefix is set.
+// The zero Info is returned for opcodes without a definition.
+func (is *InstructionSet) OpcodeInfo(prefix bool, code uint8) Info {
+	if prefix {
+		return is.infosCB[code]
+	}
+	info := is.infos[code]
+	if loadsF(code, is.ops[code]) {
+		info.Flags = Flags{opdef.Affected, opdef.Affected, opdef.Affected, opdef.Affected}
+	}
+	return info
+}
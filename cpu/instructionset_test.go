@@ -0,0 +1,79 @@
+package cpu
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// editDefs returns a copy of the CPU's defs, with a replacement applied to a file.
+func editDefs(t *testing.T, file, old, new string) fs.FS {
+	fsys := fstest.MapFS{}
+	entries, err := fs.ReadDir(DefFS(), ".")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	for _, e := range entries {
+		data, err := fs.ReadFile(DefFS(), e.Name())
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		if e.Name() == file {
+			if !assert.Contains(t, string(data), old) {
+				t.FailNow()
+			}
+			data = []byte(strings.Replace(string(data), old, new, 1))
+		}
+		fsys[e.Name()] = &fstest.MapFile{Data: data}
+	}
+	return fsys
+}
+
+func TestLoadInstructionSet(t *testing.T) {
+	t.Run("variants run side by side", func(t *testing.T) {
+		assert := assert.New(t)
+		// a nop that takes an extra cycle
+		slowNop, err := LoadInstructionSet(editDefs(t, "misc.yaml", `nop:
+  code: 0x00
+  cycles:
+`, `nop:
+  code: 0x00
+  cycles:
+    - addr: PC
+`))
+		if !assert.NoError(err) {
+			return
+		}
+		assert.Equal(2, slowNop.OpcodeInfo(false, 0x00).MinCycles)
+		assert.Equal(1, OpcodeInfo(false, 0x00).MinCycles)
+
+		var bus flatBus
+		a, b := NewCore(&bus), NewCore(&bus)
+		b.InstructionSet = slowNop
+		for _, c := range []*Core{a, b} {
+			c.IR, c.S, c.PC = 0x00, 0, 0x0101
+		}
+
+		res, err := a.StepInstruction()
+		assert.NoError(err)
+		assert.Equal(1, res.Cycles)
+		res, err = b.StepInstruction()
+		assert.NoError(err)
+		assert.Equal(2, res.Cycles)
+	})
+
+	t.Run("unknown op", func(t *testing.T) {
+		_, err := LoadInstructionSet(fstest.MapFS{"a.yaml": {Data: []byte(`
+nop:
+  code: 0x00
+  cycles:
+    - addr: PC
+      misc: NAP
+      ftch: YES
+`)}})
+		assert.EqualError(t, err, `a.yaml:5: def "nop": unknown cpu.MiscOp "NAP"`)
+	})
+}
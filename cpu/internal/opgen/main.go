@@ -0,0 +1,201 @@
+// Opgen compiles the CPU's YAML opcode definitions into static Go tables,
+// so the cpu package doesn't have to parse them at startup.
+//
+// It's run by go generate in the cpu package:
+//
+//	go run ./internal/opgen [-defs dir] [-ops file] [-o file]
+//
+// Micro-op names in the defs are mapped to the constants declared in the ops
+// file by their line comments (the same names stringer generates), so a def
+// using an unknown name fails generation, located by file & line.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/wmarshpersonal/gogeebee/cpu/opdef"
+)
+
+// opTypes are the types of the micro-op constants, one per unit.
+var opTypes = []string{"AddrSelector", "DataOp", "IDUOp", "ALUOp", "MiscOp"}
+
+// flagEffects are the identifiers of the opdef.FlagEffect values.
+var flagEffects = [...]string{
+	opdef.Unchanged: "opdef.Unchanged",
+	opdef.Reset:     "opdef.Reset",
+	opdef.Set:       "opdef.Set",
+	opdef.Affected:  "opdef.Affected",
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("opgen: ")
+
+	defsDir := flag.String("defs", "defs", "directory of YAML opcode definitions")
+	opsFile := flag.String("ops", "ops.go", "Go file declaring the micro-op constants")
+	output := flag.String("o", "opdefs_gen.go", "output file")
+	flag.Parse()
+
+	names, err := opNames(*opsFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defs, err := opdef.Parse(os.DirFS(*defsDir))
+	if err != nil {
+		// parse errors are located by file, relative to the defs dir
+		log.Fatalf("%s%c%v", *defsDir, filepath.Separator, err)
+	}
+	src, err := generate(defs, names)
+	if err != nil {
+		log.Fatalf("%s%c%v", *defsDir, filepath.Separator, err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// opNames maps the names of the micro-ops declared in a Go file to their
+// constants, by type. A constant's name is its line comment, or its identifier
+// if it has none.
+func opNames(filename string) (map[string]map[string]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]map[string]string{}
+	for _, t := range opTypes {
+		names[t] = map[string]string{}
+	}
+	for _, decl := range file.Decls {
+		decl, ok := decl.(*ast.GenDecl)
+		if !ok || decl.Tok != token.CONST {
+			continue
+		}
+		var typ string // carried over specs without a type, as with iota
+		for _, spec := range decl.Specs {
+			spec := spec.(*ast.ValueSpec)
+			if ident, ok := spec.Type.(*ast.Ident); ok {
+				typ = ident.Name
+			} else if spec.Type != nil || spec.Values != nil {
+				typ = ""
+			}
+			if names[typ] == nil {
+				continue
+			}
+			for _, ident := range spec.Names {
+				name := ident.Name
+				if spec.Comment != nil {
+					name = strings.TrimSpace(spec.Comment.Text())
+				}
+				names[typ][name] = ident.Name
+			}
+		}
+	}
+	return names, nil
+}
+
+// generate returns the Go source of the opcode tables for defs.
+func generate(defs []opdef.Def, names map[string]map[string]string) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by opgen from the YAML opcode definitions; DO NOT EDIT.\n\n")
+	b.WriteString("package cpu\n\n")
+	b.WriteString("import \"github.com/wmarshpersonal/gogeebee/cpu/opdef\"\n\n")
+
+	// opcodes, one per def
+	b.WriteString("var defOps = [...]Opcode{\n")
+	for _, def := range defs {
+		fmt.Fprintf(&b, "// %s (%s)\n{\n", def.Mnemonic, def.Pos())
+		for _, cycle := range def.Cycles {
+			var fields []string
+			for _, unit := range []struct{ field, typ, name string }{
+				{"Addr", "AddrSelector", cycle.Addr},
+				{"Data", "DataOp", cycle.Data},
+				{"IDU", "IDUOp", cycle.IDU},
+				{"ALU", "ALUOp", cycle.ALU},
+				{"Misc", "MiscOp", cycle.Misc},
+			} {
+				if unit.name == "" {
+					continue
+				}
+				ident, ok := names[unit.typ][unit.name]
+				if !ok {
+					return nil, fmt.Errorf("%s:%d: def %q: unknown %s %q",
+						def.File, cycle.Line, def.Mnemonic, unit.typ, unit.name)
+				}
+				fields = append(fields, unit.field+": "+ident)
+			}
+			if cycle.Ftch {
+				fields = append(fields, "Fetch: true")
+			}
+			fmt.Fprintf(&b, "{%s},\n", strings.Join(fields, ", "))
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("}\n\n")
+
+	// metadata, one per def
+	b.WriteString("var defInfos = [...]Info{\n")
+	for i, def := range defs {
+		effects, err := opdef.ParseFlags(def.Flags)
+		if err != nil {
+			return nil, fmt.Errorf("%s: def %q: %w", def.Pos(), def.Mnemonic, err)
+		}
+		var flags []string
+		if effects != [4]opdef.FlagEffect{} {
+			for _, e := range effects {
+				flags = append(flags, flagEffects[e])
+			}
+		}
+		fmt.Fprintf(&b, "newInfo(%s, %t, Flags{%s}, defOps[%d]),\n",
+			strconv.Quote(def.Mnemonic), def.Prefix, strings.Join(flags, ", "), i)
+	}
+	b.WriteString("}\n\n")
+
+	// opcode tables, indexing the above
+	for _, table := range []struct {
+		ops, infos string
+		prefix     bool
+	}{
+		{"operations", "infos", false},
+		{"operationsCB", "infosCB", true},
+	} {
+		var codes [0x100]int
+		for i := range codes {
+			codes[i] = -1
+		}
+		for i, def := range defs {
+			if def.Prefix == table.prefix {
+				for _, code := range def.Codes {
+					codes[code.Value] = i
+				}
+			}
+		}
+
+		for _, t := range []struct{ name, typ, defs string }{
+			{table.ops, "Opcode", "defOps"},
+			{table.infos, "Info", "defInfos"},
+		} {
+			fmt.Fprintf(&b, "var %s = [0x100]%s{\n", t.name, t.typ)
+			for code, i := range codes {
+				if i >= 0 {
+					fmt.Fprintf(&b, "0x%02X: %s[%d], // %s\n", code, t.defs, i, defs[i].Mnemonic)
+				}
+			}
+			b.WriteString("}\n\n")
+		}
+	}
+
+	return format.Source(b.Bytes())
+}
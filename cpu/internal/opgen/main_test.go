@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wmarshpersonal/gogeebee/cpu/opdef"
+)
+
+func Test_opNames(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "ops.go")
+	os.WriteFile(file, []byte(`package cpu
+
+type IDUOp uint8
+
+const (
+	Inc IDUOp = iota + 1 // ++
+	Dec                  // --
+	Set_SP
+)
+
+const other = 1
+`), 0o644)
+
+	names, err := opNames(file)
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]string{"++": "Inc", "--": "Dec", "Set_SP": "Set_SP"}, names["IDUOp"])
+		assert.Empty(t, names["ALUOp"])
+	}
+}
+
+func Test_generate(t *testing.T) {
+	names, err := opNames("../../ops.go")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defs := []opdef.Def{{
+		Mnemonic: "nop",
+		Codes:    []opdef.Code{{Value: 0x00}},
+		Flags:    "----",
+		Cycles:   []opdef.Cycle{{Addr: "PC", Ftch: true, Line: 4}},
+		File:     "misc.yaml",
+		Line:     2,
+	}}
+	src, err := generate(defs, names)
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(src), "{Addr: AddrPC, Fetch: true},")
+		assert.Contains(t, string(src), "0x00: defOps[0], // nop")
+	}
+
+	defs[0].Cycles[0].IDU = "+++"
+	_, err = generate(defs, names)
+	assert.EqualError(t, err, `misc.yaml:4: def "nop": unknown IDUOp "+++"`)
+}
@@ -0,0 +1,270 @@
+// Package opdef parses the YAML opcode definitions the CPU is built from.
+package opdef
+
+import (
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Def is an opcode definition, covering one or more opcodes.
+type Def struct {
+	Mnemonic string
+	Prefix   bool    // CB-prefixed opcodes
+	Codes    []Code  // opcodes defined, after exclusions
+	Exclude  []Code  // opcodes listed for exclusion...
+	Excluded []Code  // ...and those the code actually covered
+	Flags    string  // flags affected, e.g. "Z0HC" (see ParseFlags)
+	Cycles   []Cycle // micro-op cycles, by unit
+	File     string  // file the def was read from
+	Line     int     // line of the def in File
+}
+
+// Pos returns the def's position, as "file:line".
+func (d Def) Pos() string {
+	return fmt.Sprintf("%s:%d", d.File, d.Line)
+}
+
+// Code is an opcode covered by a def.
+type Code struct {
+	Value    uint8
+	Wildcard uint8 // bits of Value that were wildcards in the code mask
+}
+
+// Cycle is a def's cycle, with its micro-ops named as in the YAML.
+type Cycle struct {
+	Addr string
+	Data string
+	IDU  string
+	ALU  string
+	Misc string
+	Ftch bool
+	Line int `yaml:"-"` // line of the cycle in its def's file
+}
+
+type rawDef struct {
+	Code    any
+	Prefix  bool
+	Exclude any
+	Flags   string
+	Cycles  []yaml.Node
+}
+
+// Parse reads the defs from every file in the root of fsys.
+// Defs are returned sorted by mnemonic. Errors are located by file & line.
+func Parse(fsys fs.FS) ([]Def, error) {
+	files, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var defs []Def
+	seen := map[string]Def{}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		fdefs, err := parseFile(fsys, file.Name())
+		if err != nil {
+			return nil, err
+		}
+		for _, def := range fdefs {
+			if other, ok := seen[def.Mnemonic]; ok {
+				return nil, fmt.Errorf("%s: conflicting def %q (also at %s)", def.Pos(), def.Mnemonic, other.Pos())
+			}
+			seen[def.Mnemonic] = def
+			defs = append(defs, def)
+		}
+	}
+	slices.SortFunc(defs, func(a, b Def) int {
+		return strings.Compare(a.Mnemonic, b.Mnemonic)
+	})
+
+	// every opcode is defined once
+	var defined [2][0x100]*Def
+	for i := range defs {
+		def := &defs[i]
+		table := &defined[0]
+		if def.Prefix {
+			table = &defined[1]
+		}
+		for _, code := range def.Codes {
+			if other := table[code.Value]; other != nil {
+				var prefix string
+				if def.Prefix {
+					prefix = "CB"
+				}
+				return nil, fmt.Errorf("%s: def %q: opcode $%s%02X already defined by %q (%s)",
+					def.Pos(), def.Mnemonic, prefix, code.Value, other.Mnemonic, other.Pos())
+			}
+			table[code.Value] = def
+		}
+	}
+
+	return defs, nil
+}
+
+// parseFile reads the defs from a file, in the order they appear.
+func parseFile(fsys fs.FS, name string) ([]Def, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s:%d: defs must be a mapping", name, root.Line)
+	}
+
+	var defs []Def
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		def := Def{Mnemonic: key.Value, File: name, Line: key.Line}
+		fail := func(line int, err error) ([]Def, error) {
+			return nil, fmt.Errorf("%s:%d: def %q: %w", name, line, def.Mnemonic, err)
+		}
+
+		var r rawDef
+		if err := value.Decode(&r); err != nil {
+			return fail(def.Line, err)
+		}
+		if def.Codes, def.Exclude, def.Excluded, err = r.codes(); err != nil {
+			return fail(def.Line, err)
+		}
+		if def.Flags = r.Flags; def.Flags == "" {
+			def.Flags = "----"
+		} else if _, err := ParseFlags(def.Flags); err != nil {
+			return fail(def.Line, err)
+		}
+		def.Prefix = r.Prefix
+		for _, node := range r.Cycles {
+			var c Cycle
+			if err := node.Decode(&c); err != nil {
+				return fail(node.Line, err)
+			}
+			c.Line = node.Line
+			def.Cycles = append(def.Cycles, c)
+		}
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+// codes returns the def's codes less any excluded, its exclusion list and
+// the codes that were removed by it.
+func (r rawDef) codes() (codes, exclude, excluded []Code, err error) {
+	codes, err = unmaskCodes(r.Code)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if r.Exclude != nil {
+		exclude, err = unmaskCodes(r.Exclude)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		codes = slices.DeleteFunc(codes, func(c Code) bool {
+			if slices.ContainsFunc(exclude, func(e Code) bool {
+				return c.Value == e.Value
+			}) {
+				excluded = append(excluded, c)
+				return true
+			}
+			return false
+		})
+	}
+	return codes, exclude, excluded, nil
+}
+
+// unmaskCodes expands a code: an int, a mask string like "00***100", or a list of those.
+func unmaskCodes(code any) ([]Code, error) {
+	switch code := code.(type) {
+	case int:
+		if code != int(uint8(code)) {
+			return nil, fmt.Errorf("code %d out of range", code)
+		}
+		return []Code{{Value: uint8(code)}}, nil
+	case string:
+		if utf8.RuneCountInString(code) != 8 /*should be 8 chars*/ ||
+			strings.Trim(code, "01*") != "" /* should only contain "01*" */ ||
+			strings.ContainsAny(strings.Trim(code, "01"), "01") /* no broken runs of wildcard allowed */ {
+			return nil, fmt.Errorf("invalid mask %q", code)
+		}
+
+		var base, wildcard uint8
+		for _, r := range code {
+			base <<= 1
+			wildcard <<= 1
+			switch r {
+			case '1':
+				base |= 1
+			case '*':
+				wildcard |= 1
+			}
+		}
+
+		var codes []Code
+		for v := range 0x100 {
+			if uint8(v)&^wildcard == base {
+				codes = append(codes, Code{Value: uint8(v), Wildcard: wildcard})
+			}
+		}
+		return codes, nil
+	case []any:
+		var codes []Code
+		for _, code := range code {
+			c, err := unmaskCodes(code)
+			if err != nil {
+				return nil, err
+			}
+			codes = append(codes, c...)
+		}
+		return codes, nil
+	default:
+		return nil, fmt.Errorf("code must be int or string, got %T", code)
+	}
+}
+
+// FlagEffect is the effect an opcode has on a flag.
+type FlagEffect uint8
+
+const (
+	Unchanged FlagEffect = iota // -
+	Reset                       // 0
+	Set                         // 1
+	Affected                    // set or reset by the result
+)
+
+// ParseFlags parses a flags annotation: one character per flag, in Z, N, H, C order.
+// Each is either the flag's letter (affected), 0 (reset), 1 (set) or - (unchanged).
+// A def without a flags annotation leaves all flags unchanged ("----").
+func ParseFlags(s string) ([4]FlagEffect, error) {
+	var effects [4]FlagEffect
+	if len(s) != 4 {
+		return effects, fmt.Errorf("invalid flags %q: must be 4 characters", s)
+	}
+	for i := range 4 {
+		switch s[i] {
+		case "ZNHC"[i]:
+			effects[i] = Affected
+		case '0':
+			effects[i] = Reset
+		case '1':
+			effects[i] = Set
+		case '-':
+			effects[i] = Unchanged
+		default:
+			return effects, fmt.Errorf("invalid flags %q: bad character %q for %c", s, s[i], "ZNHC"[i])
+		}
+	}
+	return effects, nil
+}
@@ -0,0 +1,123 @@
+package opdef
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_unmaskCodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    any
+		want    []Code
+		wantErr bool
+	}{
+		{"int", 0x76, []Code{{Value: 0x76}}, false},
+		{"int out of range", 0x100, nil, true},
+		{"mask", "110**010", []Code{
+			{0xC2, 0b00011000},
+			{0xCA, 0b00011000},
+			{0xD2, 0b00011000},
+			{0xDA, 0b00011000},
+		}, false},
+		{"list", []any{0x01, "0000001*"}, []Code{
+			{Value: 0x01},
+			{0x02, 0b1},
+			{0x03, 0b1},
+		}, false},
+		{"short mask", "0101", nil, true},
+		{"broken wildcard run", "0*0*0000", nil, true},
+		{"bad type", 1.5, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := unmaskCodes(tt.code)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.yaml": {Data: []byte(`
+inc r:
+  code: 00***100
+  exclude: 0x34
+  cycles:
+    - addr: PC
+      alu:  r ← r + 1
+      ftch: YES
+`)},
+		"b.yaml": {Data: []byte(`
+rlc r:
+  prefix: YES
+  code: 0x00
+  cycles:
+    - addr: PC
+      ftch: YES
+`)},
+	}
+
+	defs, err := Parse(fsys)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if assert.Len(t, defs, 2) {
+		assert.Equal(t, "inc r", defs[0].Mnemonic)
+		assert.Len(t, defs[0].Codes, 7)
+		assert.NotContains(t, defs[0].Codes, Code{0x34, 0b00111000})
+		assert.Equal(t, []Code{{Value: 0x34}}, defs[0].Exclude)
+		assert.Equal(t, []Code{{0x34, 0b00111000}}, defs[0].Excluded)
+		assert.Equal(t, []Cycle{{Addr: "PC", ALU: "r ← r + 1", Ftch: true, Line: 6}}, defs[0].Cycles)
+		assert.Equal(t, "a.yaml:2", defs[0].Pos())
+		assert.True(t, defs[1].Prefix)
+	}
+
+	fsys["c.yaml"] = fsys["b.yaml"]
+	_, err = Parse(fsys)
+	assert.EqualError(t, err, `c.yaml:2: conflicting def "rlc r" (also at b.yaml:2)`)
+}
+
+func TestParse_errors(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"bad mask", `
+nop:
+  code: 01*1
+`, `a.yaml:2: def "nop": invalid mask "01*1"`},
+		{"bad flags", `
+nop:
+  code: 0x00
+  flags: Z0H
+`, `a.yaml:2: def "nop": invalid flags "Z0H": must be 4 characters`},
+		{"bad cycle", `
+nop:
+  code: 0x00
+  cycles:
+    - addr: PC
+    - [PC]
+`, `a.yaml:6: def "nop": yaml: unmarshal errors:
+  line 6: cannot unmarshal !!seq into opdef.Cycle`},
+		{"opcode defined twice", `
+nop:
+  code: 0x00
+halt:
+  code: 0000000*
+`, `a.yaml:2: def "nop": opcode $00 already defined by "halt" (a.yaml:4)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(fstest.MapFS{"a.yaml": {Data: []byte(tt.yaml)}})
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+// Package opdeflint checks the CPU's YAML opcode definitions for mistakes that
+// would otherwise only show up when the opcodes are run.
+package opdeflint
+
+import (
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+
+	"github.com/wmarshpersonal/gogeebee/cpu"
+	"github.com/wmarshpersonal/gogeebee/cpu/opdef"
+)
+
+// Illegal are the base opcodes the CPU doesn't implement. They may be left
+// undefined, or defined to lock up the CPU.
+var Illegal = []uint8{0xD3, 0xDB, 0xDD, 0xE3, 0xE4, 0xEB, 0xEC, 0xED, 0xF4, 0xFC, 0xFD}
+
+// Problem is a mistake found in the defs.
+type Problem struct {
+	File string // file of the def, if the problem is located
+	Line int    // line of the def or cycle
+	Def  string // mnemonic of the def, if the problem is located
+	Msg  string
+}
+
+func (p Problem) String() string {
+	if p.File == "" {
+		return p.Msg
+	}
+	return fmt.Sprintf("%s:%d: def %q: %s", p.File, p.Line, p.Def, p.Msg)
+}
+
+// LintFS parses the defs in fsys and lints them.
+// The error is for defs that can't be parsed at all.
+func LintFS(fsys fs.FS) ([]Problem, error) {
+	defs, err := opdef.Parse(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return Lint(defs), nil
+}
+
+// Lint checks defs, returning the problems found ordered by file & line.
+// It checks that:
+//   - every legal base opcode (bar the $CB prefix) & every CB opcode is defined
+//   - every micro-op name is known
+//   - fetch cycles don't also have Data or IDU ops, and only end a def or its false branch
+//   - every COND selects on a cc field, and is followed by a single-cycle false
+//     branch & a true branch, both ending in a fetch
+//   - every op of every cycle is valid for each opcode the def covers, e.g. that
+//     the register fields of the opcode select registers
+//   - every opcode listed for exclusion was actually covered by the def's code
+func Lint(defs []opdef.Def) []Problem {
+	var problems []Problem
+	for _, def := range defs {
+		problems = append(problems, lintDef(def)...)
+	}
+	slices.SortStableFunc(problems, func(a, b Problem) int {
+		if c := strings.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+		return a.Line - b.Line
+	})
+	return append(coverage(defs), problems...)
+}
+
+// coverage checks every opcode is defined.
+func coverage(defs []opdef.Def) []Problem {
+	var defined [2][0x100]bool
+	for _, def := range defs {
+		for _, code := range def.Codes {
+			if def.Prefix {
+				defined[1][code.Value] = true
+			} else {
+				defined[0][code.Value] = true
+			}
+		}
+	}
+
+	var problems []Problem
+	for code := range 0x100 {
+		switch {
+		case code == 0xCB:
+			if defined[0][code] {
+				problems = append(problems, Problem{Msg: "opcode $CB is the prefix and can't be defined"})
+			}
+		case !defined[0][code] && !slices.Contains(Illegal, uint8(code)):
+			problems = append(problems, Problem{Msg: fmt.Sprintf("opcode $%02X is not defined", code)})
+		}
+	}
+	for code := range 0x100 {
+		if !defined[1][code] {
+			problems = append(problems, Problem{Msg: fmt.Sprintf("opcode $CB%02X is not defined", code)})
+		}
+	}
+	return problems
+}
+
+func lintDef(def opdef.Def) []Problem {
+	var problems []Problem
+	report := func(line int, format string, args ...any) {
+		problems = append(problems, Problem{
+			File: def.File,
+			Line: line,
+			Def:  def.Mnemonic,
+			Msg:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	for _, e := range def.Exclude {
+		if !slices.ContainsFunc(def.Excluded, func(c opdef.Code) bool { return c.Value == e.Value }) {
+			report(def.Line, "excluded opcode $%02X isn't covered by the code", e.Value)
+		}
+	}
+
+	if len(def.Cycles) == 0 {
+		report(def.Line, "no cycles")
+		return problems
+	}
+
+	// resolve the cycles; ops that can't be resolved are reported & left empty
+	cycles := make([]cpu.Cycle, len(def.Cycles))
+	for i, c := range def.Cycles {
+		for _, unit := range []struct {
+			name string
+			err  error
+		}{
+			{c.Addr, cpu.OpByName(&cycles[i].Addr, c.Addr)},
+			{c.Data, cpu.OpByName(&cycles[i].Data, c.Data)},
+			{c.IDU, cpu.OpByName(&cycles[i].IDU, c.IDU)},
+			{c.ALU, cpu.OpByName(&cycles[i].ALU, c.ALU)},
+			{c.Misc, cpu.OpByName(&cycles[i].Misc, c.Misc)},
+		} {
+			if unit.err != nil {
+				report(c.Line, "unknown op %q", unit.name)
+			}
+		}
+		cycles[i].Fetch = c.Ftch
+	}
+
+	// fetches
+	cond := slices.IndexFunc(cycles, func(c cpu.Cycle) bool { return c.Misc == cpu.Cond })
+	for i, c := range cycles {
+		if !c.Fetch {
+			continue
+		}
+		if c.Data != 0 || c.IDU != 0 {
+			report(def.Cycles[i].Line, "fetch cycle can't have Data or IDU ops")
+		}
+		if i != len(cycles)-1 && (cond < 0 || i != cond+1) {
+			report(def.Cycles[i].Line, "fetch cycle before the end of the opcode")
+		}
+	}
+	if last := cycles[len(cycles)-1]; !last.Fetch && last.Misc != cpu.Lock {
+		report(def.Cycles[len(cycles)-1].Line, "last cycle doesn't fetch")
+	}
+
+	// conditions
+	for i, c := range cycles {
+		if c.Misc != cpu.Cond {
+			continue
+		}
+		line := def.Cycles[i].Line
+		switch {
+		case i != cond:
+			report(line, "more than one COND")
+		case c.Fetch:
+			report(line, "COND cycle can't fetch")
+		case i+1 >= len(cycles) || !cycles[i+1].Fetch:
+			report(line, "COND must be followed by a false branch of a single fetch cycle")
+		case i+2 >= len(cycles):
+			report(line, "COND must be followed by a true branch after the false branch")
+		}
+		for _, code := range def.Codes {
+			if code.Wildcard&0b00011000 != 0b00011000 {
+				report(line, "COND for opcode $%02X doesn't select on a cc field", code.Value)
+				break
+			}
+		}
+	}
+
+	// ops are valid for every opcode. Each error is reported for its first opcode only.
+	for i, c := range cycles {
+		seen := map[string]bool{}
+		for _, code := range def.Codes {
+			if err := check(c, code.Value); err != nil && !seen[err.Error()] {
+				seen[err.Error()] = true
+				report(def.Cycles[i].Line, "opcode $%s%02X: %v", prefix(def), code.Value, err)
+			}
+		}
+	}
+
+	return problems
+}
+
+// check runs the cycle's ops for an opcode, returning the error they panic with.
+func check(c cpu.Cycle, opcode uint8) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch r := r.(type) {
+			case cpu.InvalidRegisterError:
+				err = fmt.Errorf("invalid register %d", r.Register)
+			case cpu.InvalidOpError:
+				err = fmt.Errorf("invalid %T %v", r.Op, r.Op)
+			default:
+				panic(r)
+			}
+		}
+	}()
+
+	var s cpu.State
+	c.Addr.Do(s)
+	c.Data.WR(s, opcode)
+	c.Data.Do(s, 0)
+	c.IDU.Do(s, c.Addr)
+	c.ALU.Do(s, opcode)
+	c.Misc.Do(s, opcode)
+	return nil
+}
+
+func prefix(def opdef.Def) string {
+	if def.Prefix {
+		return "CB"
+	}
+	return ""
+}
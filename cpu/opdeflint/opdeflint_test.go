@@ -0,0 +1,173 @@
+package opdeflint
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wmarshpersonal/gogeebee/cpu"
+	"github.com/wmarshpersonal/gogeebee/cpu/opdef"
+)
+
+func TestLint_defs(t *testing.T) {
+	problems, err := LintFS(cpu.DefFS())
+	if assert.NoError(t, err) {
+		assert.Empty(t, problems)
+	}
+}
+
+func TestLint_coverage(t *testing.T) {
+	defs, err := opdef.Parse(cpu.DefFS())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defs = slices.DeleteFunc(defs, func(d opdef.Def) bool {
+		return d.Mnemonic == "halt" || d.Mnemonic == "swap r" || d.Mnemonic == "illegal"
+	})
+	defs = append(defs, opdef.Def{Mnemonic: "prefix", Codes: []opdef.Code{{Value: 0xCB}}, File: "a.yaml"})
+
+	var got []string
+	for _, p := range Lint(defs) {
+		if p.File == "" {
+			got = append(got, p.String())
+		}
+	}
+	assert.Equal(t, []string{
+		"opcode $76 is not defined",
+		"opcode $CB is the prefix and can't be defined",
+		"opcode $CB30 is not defined",
+		"opcode $CB31 is not defined",
+		"opcode $CB32 is not defined",
+		"opcode $CB33 is not defined",
+		"opcode $CB34 is not defined",
+		"opcode $CB35 is not defined",
+		"opcode $CB37 is not defined",
+	}, got)
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want []string
+	}{
+		{"valid", `
+jr cc, e:
+  code: 001**000
+  cycles:
+    - addr: PC
+      data: Z ←
+      idu:  ++
+      misc: COND
+    - addr: PC
+      ftch: YES
+    - data: W ← ALU
+      alu:  res, Z ← PCL +- Z
+    - addr: WZ
+      ftch: YES
+`, nil},
+		{"unknown op", `
+nop:
+  code: 0x00
+  cycles:
+    - addr: PC
+      alu:  A ← A * Z
+      ftch: YES
+`, []string{`a.yaml:5: def "nop": unknown op "A ← A * Z"`}},
+		{"fetch with data & idu", `
+ld a, n:
+  code: 0x3E
+  cycles:
+    - addr: PC
+      data: Z ←
+      idu:  ++
+      ftch: YES
+`, []string{`a.yaml:5: def "ld a, n": fetch cycle can't have Data or IDU ops`}},
+		{"early fetch", `
+nop:
+  code: 0x00
+  cycles:
+    - addr: PC
+      ftch: YES
+    - addr: PC
+`, []string{
+			`a.yaml:5: def "nop": fetch cycle before the end of the opcode`,
+			`a.yaml:7: def "nop": last cycle doesn't fetch`,
+		}},
+		{"COND without true branch", `
+ret cc:
+  code: 110**000
+  cycles:
+    - misc: COND
+    - addr: PC
+      ftch: YES
+`, []string{`a.yaml:5: def "ret cc": COND must be followed by a true branch after the false branch`}},
+		{"COND with long false branch", `
+ret cc:
+  code: 110**000
+  cycles:
+    - misc: COND
+    - addr: PC
+    - addr: PC
+      ftch: YES
+`, []string{
+			`a.yaml:5: def "ret cc": COND must be followed by a false branch of a single fetch cycle`,
+		}},
+		{"COND without cc field", `
+ret c:
+  code: 0xD8
+  cycles:
+    - misc: COND
+    - addr: PC
+      ftch: YES
+    - addr: PC
+      ftch: YES
+`, []string{`a.yaml:5: def "ret c": COND for opcode $D8 doesn't select on a cc field`}},
+		{"invalid register", `
+ld r, r':
+  code: 01******
+  exclude: 0x76
+  cycles:
+    - addr: PC
+      alu:  r ← r'
+      ftch: YES
+`, []string{
+			`a.yaml:6: def "ld r, r'": opcode $46: invalid register 6`,
+		}},
+		{"invalid op", `
+ldh (c), a:
+  code: 0xE2
+  cycles:
+    - addr: 0xFF00 + C
+      data: ← A
+      idu:  ++
+    - addr: PC
+      ftch: YES
+`, []string{`a.yaml:5: def "ldh (c), a": opcode $E2: invalid cpu.AddrSelector 0xFF00 + C`}},
+		{"exclusion outside code", `
+inc r:
+  code: 00***100
+  exclude: [0x34, 0x35]
+  cycles:
+    - addr: PC
+      alu:  r ← r + 1
+      ftch: YES
+`, []string{`a.yaml:2: def "inc r": excluded opcode $35 isn't covered by the code`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems, err := LintFS(fstest.MapFS{"a.yaml": {Data: []byte(tt.yaml)}})
+			if !assert.NoError(t, err) {
+				return
+			}
+			var got []string
+			for _, p := range problems {
+				if p.File != "" {
+					got = append(got, p.String())
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
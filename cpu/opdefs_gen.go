@@ -0,0 +1,1725 @@
+// Code generated by opgen from the YAML opcode definitions; DO NOT EDIT.
+
+package cpu
+
+import "github.com/wmarshpersonal/gogeebee/cpu/opdef"
+
+var defOps = [...]Opcode{
+	// adc (hl) (arithmetic.yaml:127)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrPC, ALU: ADC_Z, Fetch: true},
+	},
+	// adc n (arithmetic.yaml:267)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrPC, ALU: ADC_Z, Fetch: true},
+	},
+	// adc r (arithmetic.yaml:198)
+	{
+		{Addr: AddrPC, ALU: ADC_r, Fetch: true},
+	},
+	// add (hl) (arithmetic.yaml:118)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrPC, ALU: ADD_Z, Fetch: true},
+	},
+	// add hl, rr (arithmetic.yaml:58)
+	{
+		{ALU: L_Equals_lo_HL_plus_rr},
+		{Addr: AddrPC, ALU: H_Equals_hi_HL_plus_rr, Fetch: true},
+	},
+	// add n (arithmetic.yaml:257)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrPC, ALU: ADD_Z, Fetch: true},
+	},
+	// add r (arithmetic.yaml:190)
+	{
+		{Addr: AddrPC, ALU: ADD_r, Fetch: true},
+	},
+	// add sp, e (arithmetic.yaml:66)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{ALU: res_Z_adj_Equals_SP_Plus_ZSigned},
+		{ALU: W_Equals_res},
+		{Addr: AddrPC, Misc: SP_Equals_WZ, Fetch: true},
+	},
+	// and (hl) (arithmetic.yaml:154)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrPC, ALU: AND_Z, Fetch: true},
+	},
+	// and n (arithmetic.yaml:297)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrPC, ALU: AND_Z, Fetch: true},
+	},
+	// and r (arithmetic.yaml:222)
+	{
+		{Addr: AddrPC, ALU: AND_r, Fetch: true},
+	},
+	// bit b, (hl) (bits & shifts.yaml:153)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrPC, ALU: BIT_Z, Fetch: true},
+	},
+	// bit b, r (bits & shifts.yaml:257)
+	{
+		{Addr: AddrPC, ALU: BIT_r, Fetch: true},
+	},
+	// call cc, nn (control-flow.yaml:80)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrPC, Data: ReadW, IDU: Inc, Misc: Cond},
+		{Addr: AddrPC, Fetch: true},
+		{Addr: AddrSP, IDU: Dec},
+		{Addr: AddrSP, Data: WritePCH, IDU: Dec},
+		{Addr: AddrSP, Data: WritePCL, Misc: PC_Equals_WZ},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// call nn (control-flow.yaml:60)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrPC, Data: ReadW, IDU: Inc},
+		{Addr: AddrSP, IDU: Dec},
+		{Addr: AddrSP, Data: WritePCH, IDU: Dec},
+		{Addr: AddrSP, Data: WritePCL, Misc: PC_Equals_WZ},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// ccf (bits & shifts.yaml:50)
+	{
+		{Addr: AddrPC, ALU: CCF, Fetch: true},
+	},
+	// cp (hl) (arithmetic.yaml:181)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrPC, ALU: CP_Z, Fetch: true},
+	},
+	// cp n (arithmetic.yaml:327)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrPC, ALU: CP_Z, Fetch: true},
+	},
+	// cp r (arithmetic.yaml:246)
+	{
+		{Addr: AddrPC, ALU: CP_r, Fetch: true},
+	},
+	// cpl (bits & shifts.yaml:36)
+	{
+		{Addr: AddrPC, ALU: CPL, Fetch: true},
+	},
+	// daa (bits & shifts.yaml:29)
+	{
+		{Addr: AddrPC, ALU: DAA, Fetch: true},
+	},
+	// dec (hl) (arithmetic.yaml:91)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrHL, Data: WriteZ, ALU: DEC_Z},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// dec bc (arithmetic.yaml:30)
+	{
+		{Addr: AddrBC, IDU: Dec},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// dec de (arithmetic.yaml:37)
+	{
+		{Addr: AddrDE, IDU: Dec},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// dec hl (arithmetic.yaml:44)
+	{
+		{Addr: AddrHL, IDU: Dec},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// dec r (arithmetic.yaml:110)
+	{
+		{Addr: AddrPC, ALU: DEC_r, Fetch: true},
+	},
+	// dec sp (arithmetic.yaml:51)
+	{
+		{Addr: AddrSP, IDU: Dec},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// di (misc.yaml:29)
+	{
+		{Addr: AddrPC, Misc: Reset_IME, Fetch: true},
+	},
+	// ei (misc.yaml:23)
+	{
+		{Addr: AddrPC, Misc: Set_IME, Fetch: true},
+	},
+	// halt (misc.yaml:17)
+	{
+		{Addr: AddrPC, Misc: Halt, Fetch: true},
+	},
+	// illegal (misc.yaml:35)
+	{
+		{Misc: Lock},
+	},
+	// inc (hl) (arithmetic.yaml:80)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrHL, Data: WriteZ, ALU: INC_Z},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// inc bc (arithmetic.yaml:2)
+	{
+		{Addr: AddrBC, IDU: Inc},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// inc de (arithmetic.yaml:9)
+	{
+		{Addr: AddrDE, IDU: Inc},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// inc hl (arithmetic.yaml:16)
+	{
+		{Addr: AddrHL, IDU: Inc},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// inc r (arithmetic.yaml:102)
+	{
+		{Addr: AddrPC, ALU: INC_r, Fetch: true},
+	},
+	// inc sp (arithmetic.yaml:23)
+	{
+		{Addr: AddrSP, IDU: Inc},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// jp cc, nn (control-flow.yaml:18)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrPC, Data: ReadW, IDU: Inc, Misc: Cond},
+		{Addr: AddrPC, Fetch: true},
+		{Misc: PC_Equals_WZ},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// jp hl (control-flow.yaml:13)
+	{
+		{Addr: AddrHL, Fetch: true},
+	},
+	// jp nn (control-flow.yaml:1)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrPC, Data: ReadW, IDU: Inc},
+		{Misc: PC_Equals_WZ},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// jr cc, e (control-flow.yaml:45)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc, Misc: Cond},
+		{Addr: AddrPC, Fetch: true},
+		{Data: W_Equals_ALU, ALU: Res_Z_Equals_PCL_Plus_ZSigned},
+		{Addr: AddrWZ, Fetch: true},
+	},
+	// jr e (control-flow.yaml:35)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Data: W_Equals_ALU, ALU: Res_Z_Equals_PCL_Plus_ZSigned},
+		{Addr: AddrWZ, Fetch: true},
+	},
+	// ld (bc), a (moves (8-bit).yaml:45)
+	{
+		{Addr: AddrBC, Data: WriteA},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// ld (de), a (moves (8-bit).yaml:52)
+	{
+		{Addr: AddrDE, Data: WriteA},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// ld (hl), n (moves (8-bit).yaml:18)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrHL, Data: WriteZ},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// ld (hl), r (moves (8-bit).yaml:10)
+	{
+		{Addr: AddrHL, Data: WriteR8},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// ld (hl+), a (moves (8-bit).yaml:137)
+	{
+		{Addr: AddrHL, Data: WriteA, IDU: Inc},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// ld (hl-), a (moves (8-bit).yaml:129)
+	{
+		{Addr: AddrHL, Data: WriteA, IDU: Dec},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// ld (nn), a (moves (8-bit).yaml:145)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrPC, Data: ReadW, IDU: Inc},
+		{Addr: AddrWZ, Data: WriteA},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// ld (nn), sp (moves (16-bit).yaml:13)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrPC, Data: ReadW, IDU: Inc},
+		{Addr: AddrWZ, Data: WriteSPL, IDU: Inc},
+		{Addr: AddrWZ, Data: WriteSPH},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// ld a, (bc) (moves (8-bit).yaml:59)
+	{
+		{Addr: AddrBC, Data: ReadZ},
+		{Addr: AddrPC, ALU: LD_A_Z, Fetch: true},
+	},
+	// ld a, (de) (moves (8-bit).yaml:67)
+	{
+		{Addr: AddrDE, Data: ReadZ},
+		{Addr: AddrPC, ALU: LD_A_Z, Fetch: true},
+	},
+	// ld a, (hl+) (moves (8-bit).yaml:120)
+	{
+		{Addr: AddrHL, Data: ReadZ, IDU: Inc},
+		{Addr: AddrPC, ALU: LD_A_Z, Fetch: true},
+	},
+	// ld a, (hl-) (moves (8-bit).yaml:111)
+	{
+		{Addr: AddrHL, Data: ReadZ, IDU: Dec},
+		{Addr: AddrPC, ALU: LD_A_Z, Fetch: true},
+	},
+	// ld a, (nn) (moves (8-bit).yaml:158)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrPC, Data: ReadW, IDU: Inc},
+		{Addr: AddrWZ, Data: ReadZ},
+		{Addr: AddrPC, ALU: LD_A_Z, Fetch: true},
+	},
+	// ld hl, sp+e (moves (16-bit).yaml:36)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{ALU: L_Equals_lo_SPL_Plus_ZSigned},
+		{Addr: AddrPC, ALU: H_Equals_hi_SPL_Plus_ZSigned, Fetch: true},
+	},
+	// ld r, (hl) (moves (8-bit).yaml:1)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrPC, ALU: LD_r_Z, Fetch: true},
+	},
+	// ld r, n (moves (8-bit).yaml:35)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrPC, ALU: LD_r_Z, Fetch: true},
+	},
+	// ld r, r' (moves (8-bit).yaml:28)
+	{
+		{Addr: AddrPC, ALU: LD_r_r, Fetch: true},
+	},
+	// ld rr, nn (moves (16-bit).yaml:1)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrPC, Data: ReadW, IDU: Inc},
+		{Addr: AddrPC, Misc: RR_Equals_WZ, Fetch: true},
+	},
+	// ld sp, hl (moves (16-bit).yaml:29)
+	{
+		{Addr: AddrHL, IDU: Set_SP},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// ldh (c), a (moves (8-bit).yaml:83)
+	{
+		{Addr: AddrHI_plus_C, Data: WriteA},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// ldh (n), a (moves (8-bit).yaml:101)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrHI_plus_Z, Data: WriteA},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// ldh a, (c) (moves (8-bit).yaml:75)
+	{
+		{Addr: AddrHI_plus_C, Data: ReadZ},
+		{Addr: AddrPC, ALU: LD_A_Z, Fetch: true},
+	},
+	// ldh a, (n) (moves (8-bit).yaml:90)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrHI_plus_Z, Data: ReadZ},
+		{Addr: AddrPC, ALU: LD_A_Z, Fetch: true},
+	},
+	// nop (misc.yaml:2)
+	{
+		{Addr: AddrPC, Fetch: true},
+	},
+	// or (hl) (arithmetic.yaml:172)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrPC, ALU: OR_Z, Fetch: true},
+	},
+	// or n (arithmetic.yaml:317)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrPC, ALU: OR_Z, Fetch: true},
+	},
+	// or r (arithmetic.yaml:238)
+	{
+		{Addr: AddrPC, ALU: OR_r, Fetch: true},
+	},
+	// pop rr (moves (16-bit).yaml:47)
+	{
+		{Addr: AddrSP, Data: ReadZ, IDU: Inc},
+		{Addr: AddrSP, Data: ReadW, IDU: Inc},
+		{Addr: AddrPC, Misc: RRstk_Equals_WZ, Fetch: true},
+	},
+	// push rr (moves (16-bit).yaml:59)
+	{
+		{Addr: AddrSP, IDU: Dec},
+		{Addr: AddrSP, Data: Write_Hi_rrstk, IDU: Dec},
+		{Addr: AddrSP, Data: Write_Lo_rrstk},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// res b, (hl) (bits & shifts.yaml:163)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrHL, Data: WriteALU, ALU: RES_Z},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// res b, r (bits & shifts.yaml:266)
+	{
+		{Addr: AddrPC, ALU: RES_r, Fetch: true},
+	},
+	// ret (control-flow.yaml:104)
+	{
+		{Addr: AddrSP, Data: ReadZ, IDU: Inc},
+		{Addr: AddrSP, Data: ReadW, IDU: Inc},
+		{Misc: PC_Equals_WZ},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// ret cc (control-flow.yaml:116)
+	{
+		{Misc: Cond},
+		{Addr: AddrPC, Fetch: true},
+		{Addr: AddrSP, Data: ReadZ, IDU: Inc},
+		{Addr: AddrSP, Data: ReadW, IDU: Inc},
+		{Misc: PC_Equals_WZ},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// reti (control-flow.yaml:133)
+	{
+		{Addr: AddrSP, Data: ReadZ, IDU: Inc},
+		{Addr: AddrSP, Data: ReadW, IDU: Inc},
+		{Misc: PC_Equals_WZ_Set_IME},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// rl (hl) (bits & shifts.yaml:81)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrHL, Data: WriteALU, ALU: RL_Z},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// rl r (bits & shifts.yaml:203)
+	{
+		{Addr: AddrPC, ALU: RL_r, Fetch: true},
+	},
+	// rla (bits & shifts.yaml:15)
+	{
+		{Addr: AddrPC, ALU: RLA, Fetch: true},
+	},
+	// rlc (hl) (bits & shifts.yaml:57)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrHL, Data: WriteALU, ALU: RLC_Z},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// rlc r (bits & shifts.yaml:185)
+	{
+		{Addr: AddrPC, ALU: RLC_r, Fetch: true},
+	},
+	// rlca (bits & shifts.yaml:1)
+	{
+		{Addr: AddrPC, ALU: RLCA, Fetch: true},
+	},
+	// rr (hl) (bits & shifts.yaml:93)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrHL, Data: WriteALU, ALU: RR_Z},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// rr r (bits & shifts.yaml:212)
+	{
+		{Addr: AddrPC, ALU: RR_r, Fetch: true},
+	},
+	// rra (bits & shifts.yaml:22)
+	{
+		{Addr: AddrPC, ALU: RRA, Fetch: true},
+	},
+	// rrc (hl) (bits & shifts.yaml:69)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrHL, Data: WriteALU, ALU: RRC_Z},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// rrc r (bits & shifts.yaml:194)
+	{
+		{Addr: AddrPC, ALU: RRC_r, Fetch: true},
+	},
+	// rrca (bits & shifts.yaml:8)
+	{
+		{Addr: AddrPC, ALU: RRCA, Fetch: true},
+	},
+	// rst n (control-flow.yaml:145)
+	{
+		{Addr: AddrSP, IDU: Dec},
+		{Addr: AddrSP, Data: WritePCH, IDU: Dec},
+		{Addr: AddrSP, Data: WritePCL, Misc: PC_Equals_Addr},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// sbc (hl) (arithmetic.yaml:145)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrPC, ALU: SBC_Z, Fetch: true},
+	},
+	// sbc n (arithmetic.yaml:287)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrPC, ALU: SBC_Z, Fetch: true},
+	},
+	// sbc r (arithmetic.yaml:214)
+	{
+		{Addr: AddrPC, ALU: SBC_r, Fetch: true},
+	},
+	// scf (bits & shifts.yaml:43)
+	{
+		{Addr: AddrPC, ALU: SCF, Fetch: true},
+	},
+	// set b, (hl) (bits & shifts.yaml:174)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrHL, Data: WriteALU, ALU: SET_Z},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// set b, r (bits & shifts.yaml:274)
+	{
+		{Addr: AddrPC, ALU: SET_r, Fetch: true},
+	},
+	// sla (hl) (bits & shifts.yaml:105)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrHL, Data: WriteALU, ALU: SLA_Z},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// sla r (bits & shifts.yaml:221)
+	{
+		{Addr: AddrPC, ALU: SLA_r, Fetch: true},
+	},
+	// sra (hl) (bits & shifts.yaml:117)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrHL, Data: WriteALU, ALU: SRA_Z},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// sra r (bits & shifts.yaml:230)
+	{
+		{Addr: AddrPC, ALU: SRA_r, Fetch: true},
+	},
+	// srl (hl) (bits & shifts.yaml:141)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrHL, Data: WriteALU, ALU: SRL_Z},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// srl r (bits & shifts.yaml:248)
+	{
+		{Addr: AddrPC, ALU: SRL_r, Fetch: true},
+	},
+	// stop (misc.yaml:7)
+	{
+		{Addr: AddrPC, IDU: Inc, Misc: Stop},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// sub (hl) (arithmetic.yaml:136)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrPC, ALU: SUB_Z, Fetch: true},
+	},
+	// sub n (arithmetic.yaml:277)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrPC, ALU: SUB_Z, Fetch: true},
+	},
+	// sub r (arithmetic.yaml:206)
+	{
+		{Addr: AddrPC, ALU: SUB_r, Fetch: true},
+	},
+	// swap (hl) (bits & shifts.yaml:129)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrHL, Data: WriteALU, ALU: SWAP_Z},
+		{Addr: AddrPC, Fetch: true},
+	},
+	// swap r (bits & shifts.yaml:239)
+	{
+		{Addr: AddrPC, ALU: SWAP_r, Fetch: true},
+	},
+	// xor (hl) (arithmetic.yaml:163)
+	{
+		{Addr: AddrHL, Data: ReadZ},
+		{Addr: AddrPC, ALU: XOR_Z, Fetch: true},
+	},
+	// xor n (arithmetic.yaml:307)
+	{
+		{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+		{Addr: AddrPC, ALU: XOR_Z, Fetch: true},
+	},
+	// xor r (arithmetic.yaml:230)
+	{
+		{Addr: AddrPC, ALU: XOR_r, Fetch: true},
+	},
+}
+
+var defInfos = [...]Info{
+	newInfo("adc (hl)", false, Flags{opdef.Affected, opdef.Reset, opdef.Affected, opdef.Affected}, defOps[0]),
+	newInfo("adc n", false, Flags{opdef.Affected, opdef.Reset, opdef.Affected, opdef.Affected}, defOps[1]),
+	newInfo("adc r", false, Flags{opdef.Affected, opdef.Reset, opdef.Affected, opdef.Affected}, defOps[2]),
+	newInfo("add (hl)", false, Flags{opdef.Affected, opdef.Reset, opdef.Affected, opdef.Affected}, defOps[3]),
+	newInfo("add hl, rr", false, Flags{opdef.Unchanged, opdef.Reset, opdef.Affected, opdef.Affected}, defOps[4]),
+	newInfo("add n", false, Flags{opdef.Affected, opdef.Reset, opdef.Affected, opdef.Affected}, defOps[5]),
+	newInfo("add r", false, Flags{opdef.Affected, opdef.Reset, opdef.Affected, opdef.Affected}, defOps[6]),
+	newInfo("add sp, e", false, Flags{opdef.Reset, opdef.Reset, opdef.Affected, opdef.Affected}, defOps[7]),
+	newInfo("and (hl)", false, Flags{opdef.Affected, opdef.Reset, opdef.Set, opdef.Reset}, defOps[8]),
+	newInfo("and n", false, Flags{opdef.Affected, opdef.Reset, opdef.Set, opdef.Reset}, defOps[9]),
+	newInfo("and r", false, Flags{opdef.Affected, opdef.Reset, opdef.Set, opdef.Reset}, defOps[10]),
+	newInfo("bit b, (hl)", true, Flags{opdef.Affected, opdef.Reset, opdef.Set, opdef.Unchanged}, defOps[11]),
+	newInfo("bit b, r", true, Flags{opdef.Affected, opdef.Reset, opdef.Set, opdef.Unchanged}, defOps[12]),
+	newInfo("call cc, nn", false, Flags{}, defOps[13]),
+	newInfo("call nn", false, Flags{}, defOps[14]),
+	newInfo("ccf", false, Flags{opdef.Unchanged, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[15]),
+	newInfo("cp (hl)", false, Flags{opdef.Affected, opdef.Set, opdef.Affected, opdef.Affected}, defOps[16]),
+	newInfo("cp n", false, Flags{opdef.Affected, opdef.Set, opdef.Affected, opdef.Affected}, defOps[17]),
+	newInfo("cp r", false, Flags{opdef.Affected, opdef.Set, opdef.Affected, opdef.Affected}, defOps[18]),
+	newInfo("cpl", false, Flags{opdef.Unchanged, opdef.Set, opdef.Set, opdef.Unchanged}, defOps[19]),
+	newInfo("daa", false, Flags{opdef.Affected, opdef.Unchanged, opdef.Reset, opdef.Affected}, defOps[20]),
+	newInfo("dec (hl)", false, Flags{opdef.Affected, opdef.Set, opdef.Affected, opdef.Unchanged}, defOps[21]),
+	newInfo("dec bc", false, Flags{}, defOps[22]),
+	newInfo("dec de", false, Flags{}, defOps[23]),
+	newInfo("dec hl", false, Flags{}, defOps[24]),
+	newInfo("dec r", false, Flags{opdef.Affected, opdef.Set, opdef.Affected, opdef.Unchanged}, defOps[25]),
+	newInfo("dec sp", false, Flags{}, defOps[26]),
+	newInfo("di", false, Flags{}, defOps[27]),
+	newInfo("ei", false, Flags{}, defOps[28]),
+	newInfo("halt", false, Flags{}, defOps[29]),
+	newInfo("illegal", false, Flags{}, defOps[30]),
+	newInfo("inc (hl)", false, Flags{opdef.Affected, opdef.Reset, opdef.Affected, opdef.Unchanged}, defOps[31]),
+	newInfo("inc bc", false, Flags{}, defOps[32]),
+	newInfo("inc de", false, Flags{}, defOps[33]),
+	newInfo("inc hl", false, Flags{}, defOps[34]),
+	newInfo("inc r", false, Flags{opdef.Affected, opdef.Reset, opdef.Affected, opdef.Unchanged}, defOps[35]),
+	newInfo("inc sp", false, Flags{}, defOps[36]),
+	newInfo("jp cc, nn", false, Flags{}, defOps[37]),
+	newInfo("jp hl", false, Flags{}, defOps[38]),
+	newInfo("jp nn", false, Flags{}, defOps[39]),
+	newInfo("jr cc, e", false, Flags{}, defOps[40]),
+	newInfo("jr e", false, Flags{}, defOps[41]),
+	newInfo("ld (bc), a", false, Flags{}, defOps[42]),
+	newInfo("ld (de), a", false, Flags{}, defOps[43]),
+	newInfo("ld (hl), n", false, Flags{}, defOps[44]),
+	newInfo("ld (hl), r", false, Flags{}, defOps[45]),
+	newInfo("ld (hl+), a", false, Flags{}, defOps[46]),
+	newInfo("ld (hl-), a", false, Flags{}, defOps[47]),
+	newInfo("ld (nn), a", false, Flags{}, defOps[48]),
+	newInfo("ld (nn), sp", false, Flags{}, defOps[49]),
+	newInfo("ld a, (bc)", false, Flags{}, defOps[50]),
+	newInfo("ld a, (de)", false, Flags{}, defOps[51]),
+	newInfo("ld a, (hl+)", false, Flags{}, defOps[52]),
+	newInfo("ld a, (hl-)", false, Flags{}, defOps[53]),
+	newInfo("ld a, (nn)", false, Flags{}, defOps[54]),
+	newInfo("ld hl, sp+e", false, Flags{opdef.Reset, opdef.Reset, opdef.Affected, opdef.Affected}, defOps[55]),
+	newInfo("ld r, (hl)", false, Flags{}, defOps[56]),
+	newInfo("ld r, n", false, Flags{}, defOps[57]),
+	newInfo("ld r, r'", false, Flags{}, defOps[58]),
+	newInfo("ld rr, nn", false, Flags{}, defOps[59]),
+	newInfo("ld sp, hl", false, Flags{}, defOps[60]),
+	newInfo("ldh (c), a", false, Flags{}, defOps[61]),
+	newInfo("ldh (n), a", false, Flags{}, defOps[62]),
+	newInfo("ldh a, (c)", false, Flags{}, defOps[63]),
+	newInfo("ldh a, (n)", false, Flags{}, defOps[64]),
+	newInfo("nop", false, Flags{}, defOps[65]),
+	newInfo("or (hl)", false, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Reset}, defOps[66]),
+	newInfo("or n", false, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Reset}, defOps[67]),
+	newInfo("or r", false, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Reset}, defOps[68]),
+	newInfo("pop rr", false, Flags{}, defOps[69]),
+	newInfo("push rr", false, Flags{}, defOps[70]),
+	newInfo("res b, (hl)", true, Flags{}, defOps[71]),
+	newInfo("res b, r", true, Flags{}, defOps[72]),
+	newInfo("ret", false, Flags{}, defOps[73]),
+	newInfo("ret cc", false, Flags{}, defOps[74]),
+	newInfo("reti", false, Flags{}, defOps[75]),
+	newInfo("rl (hl)", true, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[76]),
+	newInfo("rl r", true, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[77]),
+	newInfo("rla", false, Flags{opdef.Reset, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[78]),
+	newInfo("rlc (hl)", true, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[79]),
+	newInfo("rlc r", true, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[80]),
+	newInfo("rlca", false, Flags{opdef.Reset, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[81]),
+	newInfo("rr (hl)", true, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[82]),
+	newInfo("rr r", true, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[83]),
+	newInfo("rra", false, Flags{opdef.Reset, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[84]),
+	newInfo("rrc (hl)", true, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[85]),
+	newInfo("rrc r", true, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[86]),
+	newInfo("rrca", false, Flags{opdef.Reset, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[87]),
+	newInfo("rst n", false, Flags{}, defOps[88]),
+	newInfo("sbc (hl)", false, Flags{opdef.Affected, opdef.Set, opdef.Affected, opdef.Affected}, defOps[89]),
+	newInfo("sbc n", false, Flags{opdef.Affected, opdef.Set, opdef.Affected, opdef.Affected}, defOps[90]),
+	newInfo("sbc r", false, Flags{opdef.Affected, opdef.Set, opdef.Affected, opdef.Affected}, defOps[91]),
+	newInfo("scf", false, Flags{opdef.Unchanged, opdef.Reset, opdef.Reset, opdef.Set}, defOps[92]),
+	newInfo("set b, (hl)", true, Flags{}, defOps[93]),
+	newInfo("set b, r", true, Flags{}, defOps[94]),
+	newInfo("sla (hl)", true, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[95]),
+	newInfo("sla r", true, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[96]),
+	newInfo("sra (hl)", true, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[97]),
+	newInfo("sra r", true, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[98]),
+	newInfo("srl (hl)", true, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[99]),
+	newInfo("srl r", true, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Affected}, defOps[100]),
+	newInfo("stop", false, Flags{}, defOps[101]),
+	newInfo("sub (hl)", false, Flags{opdef.Affected, opdef.Set, opdef.Affected, opdef.Affected}, defOps[102]),
+	newInfo("sub n", false, Flags{opdef.Affected, opdef.Set, opdef.Affected, opdef.Affected}, defOps[103]),
+	newInfo("sub r", false, Flags{opdef.Affected, opdef.Set, opdef.Affected, opdef.Affected}, defOps[104]),
+	newInfo("swap (hl)", true, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Reset}, defOps[105]),
+	newInfo("swap r", true, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Reset}, defOps[106]),
+	newInfo("xor (hl)", false, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Reset}, defOps[107]),
+	newInfo("xor n", false, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Reset}, defOps[108]),
+	newInfo("xor r", false, Flags{opdef.Affected, opdef.Reset, opdef.Reset, opdef.Reset}, defOps[109]),
+}
+
+var operations = [0x100]Opcode{
+	0x00: defOps[65],  // nop
+	0x01: defOps[59],  // ld rr, nn
+	0x02: defOps[42],  // ld (bc), a
+	0x03: defOps[32],  // inc bc
+	0x04: defOps[35],  // inc r
+	0x05: defOps[25],  // dec r
+	0x06: defOps[57],  // ld r, n
+	0x07: defOps[81],  // rlca
+	0x08: defOps[49],  // ld (nn), sp
+	0x09: defOps[4],   // add hl, rr
+	0x0A: defOps[50],  // ld a, (bc)
+	0x0B: defOps[22],  // dec bc
+	0x0C: defOps[35],  // inc r
+	0x0D: defOps[25],  // dec r
+	0x0E: defOps[57],  // ld r, n
+	0x0F: defOps[87],  // rrca
+	0x10: defOps[101], // stop
+	0x11: defOps[59],  // ld rr, nn
+	0x12: defOps[43],  // ld (de), a
+	0x13: defOps[33],  // inc de
+	0x14: defOps[35],  // inc r
+	0x15: defOps[25],  // dec r
+	0x16: defOps[57],  // ld r, n
+	0x17: defOps[78],  // rla
+	0x18: defOps[41],  // jr e
+	0x19: defOps[4],   // add hl, rr
+	0x1A: defOps[51],  // ld a, (de)
+	0x1B: defOps[23],  // dec de
+	0x1C: defOps[35],  // inc r
+	0x1D: defOps[25],  // dec r
+	0x1E: defOps[57],  // ld r, n
+	0x1F: defOps[84],  // rra
+	0x20: defOps[40],  // jr cc, e
+	0x21: defOps[59],  // ld rr, nn
+	0x22: defOps[46],  // ld (hl+), a
+	0x23: defOps[34],  // inc hl
+	0x24: defOps[35],  // inc r
+	0x25: defOps[25],  // dec r
+	0x26: defOps[57],  // ld r, n
+	0x27: defOps[20],  // daa
+	0x28: defOps[40],  // jr cc, e
+	0x29: defOps[4],   // add hl, rr
+	0x2A: defOps[52],  // ld a, (hl+)
+	0x2B: defOps[24],  // dec hl
+	0x2C: defOps[35],  // inc r
+	0x2D: defOps[25],  // dec r
+	0x2E: defOps[57],  // ld r, n
+	0x2F: defOps[19],  // cpl
+	0x30: defOps[40],  // jr cc, e
+	0x31: defOps[59],  // ld rr, nn
+	0x32: defOps[47],  // ld (hl-), a
+	0x33: defOps[36],  // inc sp
+	0x34: defOps[31],  // inc (hl)
+	0x35: defOps[21],  // dec (hl)
+	0x36: defOps[44],  // ld (hl), n
+	0x37: defOps[92],  // scf
+	0x38: defOps[40],  // jr cc, e
+	0x39: defOps[4],   // add hl, rr
+	0x3A: defOps[53],  // ld a, (hl-)
+	0x3B: defOps[26],  // dec sp
+	0x3C: defOps[35],  // inc r
+	0x3D: defOps[25],  // dec r
+	0x3E: defOps[57],  // ld r, n
+	0x3F: defOps[15],  // ccf
+	0x40: defOps[58],  // ld r, r'
+	0x41: defOps[58],  // ld r, r'
+	0x42: defOps[58],  // ld r, r'
+	0x43: defOps[58],  // ld r, r'
+	0x44: defOps[58],  // ld r, r'
+	0x45: defOps[58],  // ld r, r'
+	0x46: defOps[56],  // ld r, (hl)
+	0x47: defOps[58],  // ld r, r'
+	0x48: defOps[58],  // ld r, r'
+	0x49: defOps[58],  // ld r, r'
+	0x4A: defOps[58],  // ld r, r'
+	0x4B: defOps[58],  // ld r, r'
+	0x4C: defOps[58],  // ld r, r'
+	0x4D: defOps[58],  // ld r, r'
+	0x4E: defOps[56],  // ld r, (hl)
+	0x4F: defOps[58],  // ld r, r'
+	0x50: defOps[58],  // ld r, r'
+	0x51: defOps[58],  // ld r, r'
+	0x52: defOps[58],  // ld r, r'
+	0x53: defOps[58],  // ld r, r'
+	0x54: defOps[58],  // ld r, r'
+	0x55: defOps[58],  // ld r, r'
+	0x56: defOps[56],  // ld r, (hl)
+	0x57: defOps[58],  // ld r, r'
+	0x58: defOps[58],  // ld r, r'
+	0x59: defOps[58],  // ld r, r'
+	0x5A: defOps[58],  // ld r, r'
+	0x5B: defOps[58],  // ld r, r'
+	0x5C: defOps[58],  // ld r, r'
+	0x5D: defOps[58],  // ld r, r'
+	0x5E: defOps[56],  // ld r, (hl)
+	0x5F: defOps[58],  // ld r, r'
+	0x60: defOps[58],  // ld r, r'
+	0x61: defOps[58],  // ld r, r'
+	0x62: defOps[58],  // ld r, r'
+	0x63: defOps[58],  // ld r, r'
+	0x64: defOps[58],  // ld r, r'
+	0x65: defOps[58],  // ld r, r'
+	0x66: defOps[56],  // ld r, (hl)
+	0x67: defOps[58],  // ld r, r'
+	0x68: defOps[58],  // ld r, r'
+	0x69: defOps[58],  // ld r, r'
+	0x6A: defOps[58],  // ld r, r'
+	0x6B: defOps[58],  // ld r, r'
+	0x6C: defOps[58],  // ld r, r'
+	0x6D: defOps[58],  // ld r, r'
+	0x6E: defOps[56],  // ld r, (hl)
+	0x6F: defOps[58],  // ld r, r'
+	0x70: defOps[45],  // ld (hl), r
+	0x71: defOps[45],  // ld (hl), r
+	0x72: defOps[45],  // ld (hl), r
+	0x73: defOps[45],  // ld (hl), r
+	0x74: defOps[45],  // ld (hl), r
+	0x75: defOps[45],  // ld (hl), r
+	0x76: defOps[29],  // halt
+	0x77: defOps[45],  // ld (hl), r
+	0x78: defOps[58],  // ld r, r'
+	0x79: defOps[58],  // ld r, r'
+	0x7A: defOps[58],  // ld r, r'
+	0x7B: defOps[58],  // ld r, r'
+	0x7C: defOps[58],  // ld r, r'
+	0x7D: defOps[58],  // ld r, r'
+	0x7E: defOps[56],  // ld r, (hl)
+	0x7F: defOps[58],  // ld r, r'
+	0x80: defOps[6],   // add r
+	0x81: defOps[6],   // add r
+	0x82: defOps[6],   // add r
+	0x83: defOps[6],   // add r
+	0x84: defOps[6],   // add r
+	0x85: defOps[6],   // add r
+	0x86: defOps[3],   // add (hl)
+	0x87: defOps[6],   // add r
+	0x88: defOps[2],   // adc r
+	0x89: defOps[2],   // adc r
+	0x8A: defOps[2],   // adc r
+	0x8B: defOps[2],   // adc r
+	0x8C: defOps[2],   // adc r
+	0x8D: defOps[2],   // adc r
+	0x8E: defOps[0],   // adc (hl)
+	0x8F: defOps[2],   // adc r
+	0x90: defOps[104], // sub r
+	0x91: defOps[104], // sub r
+	0x92: defOps[104], // sub r
+	0x93: defOps[104], // sub r
+	0x94: defOps[104], // sub r
+	0x95: defOps[104], // sub r
+	0x96: defOps[102], // sub (hl)
+	0x97: defOps[104], // sub r
+	0x98: defOps[91],  // sbc r
+	0x99: defOps[91],  // sbc r
+	0x9A: defOps[91],  // sbc r
+	0x9B: defOps[91],  // sbc r
+	0x9C: defOps[91],  // sbc r
+	0x9D: defOps[91],  // sbc r
+	0x9E: defOps[89],  // sbc (hl)
+	0x9F: defOps[91],  // sbc r
+	0xA0: defOps[10],  // and r
+	0xA1: defOps[10],  // and r
+	0xA2: defOps[10],  // and r
+	0xA3: defOps[10],  // and r
+	0xA4: defOps[10],  // and r
+	0xA5: defOps[10],  // and r
+	0xA6: defOps[8],   // and (hl)
+	0xA7: defOps[10],  // and r
+	0xA8: defOps[109], // xor r
+	0xA9: defOps[109], // xor r
+	0xAA: defOps[109], // xor r
+	0xAB: defOps[109], // xor r
+	0xAC: defOps[109], // xor r
+	0xAD: defOps[109], // xor r
+	0xAE: defOps[107], // xor (hl)
+	0xAF: defOps[109], // xor r
+	0xB0: defOps[68],  // or r
+	0xB1: defOps[68],  // or r
+	0xB2: defOps[68],  // or r
+	0xB3: defOps[68],  // or r
+	0xB4: defOps[68],  // or r
+	0xB5: defOps[68],  // or r
+	0xB6: defOps[66],  // or (hl)
+	0xB7: defOps[68],  // or r
+	0xB8: defOps[18],  // cp r
+	0xB9: defOps[18],  // cp r
+	0xBA: defOps[18],  // cp r
+	0xBB: defOps[18],  // cp r
+	0xBC: defOps[18],  // cp r
+	0xBD: defOps[18],  // cp r
+	0xBE: defOps[16],  // cp (hl)
+	0xBF: defOps[18],  // cp r
+	0xC0: defOps[74],  // ret cc
+	0xC1: defOps[69],  // pop rr
+	0xC2: defOps[37],  // jp cc, nn
+	0xC3: defOps[39],  // jp nn
+	0xC4: defOps[13],  // call cc, nn
+	0xC5: defOps[70],  // push rr
+	0xC6: defOps[5],   // add n
+	0xC7: defOps[88],  // rst n
+	0xC8: defOps[74],  // ret cc
+	0xC9: defOps[73],  // ret
+	0xCA: defOps[37],  // jp cc, nn
+	0xCC: defOps[13],  // call cc, nn
+	0xCD: defOps[14],  // call nn
+	0xCE: defOps[1],   // adc n
+	0xCF: defOps[88],  // rst n
+	0xD0: defOps[74],  // ret cc
+	0xD1: defOps[69],  // pop rr
+	0xD2: defOps[37],  // jp cc, nn
+	0xD3: defOps[30],  // illegal
+	0xD4: defOps[13],  // call cc, nn
+	0xD5: defOps[70],  // push rr
+	0xD6: defOps[103], // sub n
+	0xD7: defOps[88],  // rst n
+	0xD8: defOps[74],  // ret cc
+	0xD9: defOps[75],  // reti
+	0xDA: defOps[37],  // jp cc, nn
+	0xDB: defOps[30],  // illegal
+	0xDC: defOps[13],  // call cc, nn
+	0xDD: defOps[30],  // illegal
+	0xDE: defOps[90],  // sbc n
+	0xDF: defOps[88],  // rst n
+	0xE0: defOps[62],  // ldh (n), a
+	0xE1: defOps[69],  // pop rr
+	0xE2: defOps[61],  // ldh (c), a
+	0xE3: defOps[30],  // illegal
+	0xE4: defOps[30],  // illegal
+	0xE5: defOps[70],  // push rr
+	0xE6: defOps[9],   // and n
+	0xE7: defOps[88],  // rst n
+	0xE8: defOps[7],   // add sp, e
+	0xE9: defOps[38],  // jp hl
+	0xEA: defOps[48],  // ld (nn), a
+	0xEB: defOps[30],  // illegal
+	0xEC: defOps[30],  // illegal
+	0xED: defOps[30],  // illegal
+	0xEE: defOps[108], // xor n
+	0xEF: defOps[88],  // rst n
+	0xF0: defOps[64],  // ldh a, (n)
+	0xF1: defOps[69],  // pop rr
+	0xF2: defOps[63],  // ldh a, (c)
+	0xF3: defOps[27],  // di
+	0xF4: defOps[30],  // illegal
+	0xF5: defOps[70],  // push rr
+	0xF6: defOps[67],  // or n
+	0xF7: defOps[88],  // rst n
+	0xF8: defOps[55],  // ld hl, sp+e
+	0xF9: defOps[60],  // ld sp, hl
+	0xFA: defOps[54],  // ld a, (nn)
+	0xFB: defOps[28],  // ei
+	0xFC: defOps[30],  // illegal
+	0xFD: defOps[30],  // illegal
+	0xFE: defOps[17],  // cp n
+	0xFF: defOps[88],  // rst n
+}
+
+var infos = [0x100]Info{
+	0x00: defInfos[65],  // nop
+	0x01: defInfos[59],  // ld rr, nn
+	0x02: defInfos[42],  // ld (bc), a
+	0x03: defInfos[32],  // inc bc
+	0x04: defInfos[35],  // inc r
+	0x05: defInfos[25],  // dec r
+	0x06: defInfos[57],  // ld r, n
+	0x07: defInfos[81],  // rlca
+	0x08: defInfos[49],  // ld (nn), sp
+	0x09: defInfos[4],   // add hl, rr
+	0x0A: defInfos[50],  // ld a, (bc)
+	0x0B: defInfos[22],  // dec bc
+	0x0C: defInfos[35],  // inc r
+	0x0D: defInfos[25],  // dec r
+	0x0E: defInfos[57],  // ld r, n
+	0x0F: defInfos[87],  // rrca
+	0x10: defInfos[101], // stop
+	0x11: defInfos[59],  // ld rr, nn
+	0x12: defInfos[43],  // ld (de), a
+	0x13: defInfos[33],  // inc de
+	0x14: defInfos[35],  // inc r
+	0x15: defInfos[25],  // dec r
+	0x16: defInfos[57],  // ld r, n
+	0x17: defInfos[78],  // rla
+	0x18: defInfos[41],  // jr e
+	0x19: defInfos[4],   // add hl, rr
+	0x1A: defInfos[51],  // ld a, (de)
+	0x1B: defInfos[23],  // dec de
+	0x1C: defInfos[35],  // inc r
+	0x1D: defInfos[25],  // dec r
+	0x1E: defInfos[57],  // ld r, n
+	0x1F: defInfos[84],  // rra
+	0x20: defInfos[40],  // jr cc, e
+	0x21: defInfos[59],  // ld rr, nn
+	0x22: defInfos[46],  // ld (hl+), a
+	0x23: defInfos[34],  // inc hl
+	0x24: defInfos[35],  // inc r
+	0x25: defInfos[25],  // dec r
+	0x26: defInfos[57],  // ld r, n
+	0x27: defInfos[20],  // daa
+	0x28: defInfos[40],  // jr cc, e
+	0x29: defInfos[4],   // add hl, rr
+	0x2A: defInfos[52],  // ld a, (hl+)
+	0x2B: defInfos[24],  // dec hl
+	0x2C: defInfos[35],  // inc r
+	0x2D: defInfos[25],  // dec r
+	0x2E: defInfos[57],  // ld r, n
+	0x2F: defInfos[19],  // cpl
+	0x30: defInfos[40],  // jr cc, e
+	0x31: defInfos[59],  // ld rr, nn
+	0x32: defInfos[47],  // ld (hl-), a
+	0x33: defInfos[36],  // inc sp
+	0x34: defInfos[31],  // inc (hl)
+	0x35: defInfos[21],  // dec (hl)
+	0x36: defInfos[44],  // ld (hl), n
+	0x37: defInfos[92],  // scf
+	0x38: defInfos[40],  // jr cc, e
+	0x39: defInfos[4],   // add hl, rr
+	0x3A: defInfos[53],  // ld a, (hl-)
+	0x3B: defInfos[26],  // dec sp
+	0x3C: defInfos[35],  // inc r
+	0x3D: defInfos[25],  // dec r
+	0x3E: defInfos[57],  // ld r, n
+	0x3F: defInfos[15],  // ccf
+	0x40: defInfos[58],  // ld r, r'
+	0x41: defInfos[58],  // ld r, r'
+	0x42: defInfos[58],  // ld r, r'
+	0x43: defInfos[58],  // ld r, r'
+	0x44: defInfos[58],  // ld r, r'
+	0x45: defInfos[58],  // ld r, r'
+	0x46: defInfos[56],  // ld r, (hl)
+	0x47: defInfos[58],  // ld r, r'
+	0x48: defInfos[58],  // ld r, r'
+	0x49: defInfos[58],  // ld r, r'
+	0x4A: defInfos[58],  // ld r, r'
+	0x4B: defInfos[58],  // ld r, r'
+	0x4C: defInfos[58],  // ld r, r'
+	0x4D: defInfos[58],  // ld r, r'
+	0x4E: defInfos[56],  // ld r, (hl)
+	0x4F: defInfos[58],  // ld r, r'
+	0x50: defInfos[58],  // ld r, r'
+	0x51: defInfos[58],  // ld r, r'
+	0x52: defInfos[58],  // ld r, r'
+	0x53: defInfos[58],  // ld r, r'
+	0x54: defInfos[58],  // ld r, r'
+	0x55: defInfos[58],  // ld r, r'
+	0x56: defInfos[56],  // ld r, (hl)
+	0x57: defInfos[58],  // ld r, r'
+	0x58: defInfos[58],  // ld r, r'
+	0x59: defInfos[58],  // ld r, r'
+	0x5A: defInfos[58],  // ld r, r'
+	0x5B: defInfos[58],  // ld r, r'
+	0x5C: defInfos[58],  // ld r, r'
+	0x5D: defInfos[58],  // ld r, r'
+	0x5E: defInfos[56],  // ld r, (hl)
+	0x5F: defInfos[58],  // ld r, r'
+	0x60: defInfos[58],  // ld r, r'
+	0x61: defInfos[58],  // ld r, r'
+	0x62: defInfos[58],  // ld r, r'
+	0x63: defInfos[58],  // ld r, r'
+	0x64: defInfos[58],  // ld r, r'
+	0x65: defInfos[58],  // ld r, r'
+	0x66: defInfos[56],  // ld r, (hl)
+	0x67: defInfos[58],  // ld r, r'
+	0x68: defInfos[58],  // ld r, r'
+	0x69: defInfos[58],  // ld r, r'
+	0x6A: defInfos[58],  // ld r, r'
+	0x6B: defInfos[58],  // ld r, r'
+	0x6C: defInfos[58],  // ld r, r'
+	0x6D: defInfos[58],  // ld r, r'
+	0x6E: defInfos[56],  // ld r, (hl)
+	0x6F: defInfos[58],  // ld r, r'
+	0x70: defInfos[45],  // ld (hl), r
+	0x71: defInfos[45],  // ld (hl), r
+	0x72: defInfos[45],  // ld (hl), r
+	0x73: defInfos[45],  // ld (hl), r
+	0x74: defInfos[45],  // ld (hl), r
+	0x75: defInfos[45],  // ld (hl), r
+	0x76: defInfos[29],  // halt
+	0x77: defInfos[45],  // ld (hl), r
+	0x78: defInfos[58],  // ld r, r'
+	0x79: defInfos[58],  // ld r, r'
+	0x7A: defInfos[58],  // ld r, r'
+	0x7B: defInfos[58],  // ld r, r'
+	0x7C: defInfos[58],  // ld r, r'
+	0x7D: defInfos[58],  // ld r, r'
+	0x7E: defInfos[56],  // ld r, (hl)
+	0x7F: defInfos[58],  // ld r, r'
+	0x80: defInfos[6],   // add r
+	0x81: defInfos[6],   // add r
+	0x82: defInfos[6],   // add r
+	0x83: defInfos[6],   // add r
+	0x84: defInfos[6],   // add r
+	0x85: defInfos[6],   // add r
+	0x86: defInfos[3],   // add (hl)
+	0x87: defInfos[6],   // add r
+	0x88: defInfos[2],   // adc r
+	0x89: defInfos[2],   // adc r
+	0x8A: defInfos[2],   // adc r
+	0x8B: defInfos[2],   // adc r
+	0x8C: defInfos[2],   // adc r
+	0x8D: defInfos[2],   // adc r
+	0x8E: defInfos[0],   // adc (hl)
+	0x8F: defInfos[2],   // adc r
+	0x90: defInfos[104], // sub r
+	0x91: defInfos[104], // sub r
+	0x92: defInfos[104], // sub r
+	0x93: defInfos[104], // sub r
+	0x94: defInfos[104], // sub r
+	0x95: defInfos[104], // sub r
+	0x96: defInfos[102], // sub (hl)
+	0x97: defInfos[104], // sub r
+	0x98: defInfos[91],  // sbc r
+	0x99: defInfos[91],  // sbc r
+	0x9A: defInfos[91],  // sbc r
+	0x9B: defInfos[91],  // sbc r
+	0x9C: defInfos[91],  // sbc r
+	0x9D: defInfos[91],  // sbc r
+	0x9E: defInfos[89],  // sbc (hl)
+	0x9F: defInfos[91],  // sbc r
+	0xA0: defInfos[10],  // and r
+	0xA1: defInfos[10],  // and r
+	0xA2: defInfos[10],  // and r
+	0xA3: defInfos[10],  // and r
+	0xA4: defInfos[10],  // and r
+	0xA5: defInfos[10],  // and r
+	0xA6: defInfos[8],   // and (hl)
+	0xA7: defInfos[10],  // and r
+	0xA8: defInfos[109], // xor r
+	0xA9: defInfos[109], // xor r
+	0xAA: defInfos[109], // xor r
+	0xAB: defInfos[109], // xor r
+	0xAC: defInfos[109], // xor r
+	0xAD: defInfos[109], // xor r
+	0xAE: defInfos[107], // xor (hl)
+	0xAF: defInfos[109], // xor r
+	0xB0: defInfos[68],  // or r
+	0xB1: defInfos[68],  // or r
+	0xB2: defInfos[68],  // or r
+	0xB3: defInfos[68],  // or r
+	0xB4: defInfos[68],  // or r
+	0xB5: defInfos[68],  // or r
+	0xB6: defInfos[66],  // or (hl)
+	0xB7: defInfos[68],  // or r
+	0xB8: defInfos[18],  // cp r
+	0xB9: defInfos[18],  // cp r
+	0xBA: defInfos[18],  // cp r
+	0xBB: defInfos[18],  // cp r
+	0xBC: defInfos[18],  // cp r
+	0xBD: defInfos[18],  // cp r
+	0xBE: defInfos[16],  // cp (hl)
+	0xBF: defInfos[18],  // cp r
+	0xC0: defInfos[74],  // ret cc
+	0xC1: defInfos[69],  // pop rr
+	0xC2: defInfos[37],  // jp cc, nn
+	0xC3: defInfos[39],  // jp nn
+	0xC4: defInfos[13],  // call cc, nn
+	0xC5: defInfos[70],  // push rr
+	0xC6: defInfos[5],   // add n
+	0xC7: defInfos[88],  // rst n
+	0xC8: defInfos[74],  // ret cc
+	0xC9: defInfos[73],  // ret
+	0xCA: defInfos[37],  // jp cc, nn
+	0xCC: defInfos[13],  // call cc, nn
+	0xCD: defInfos[14],  // call nn
+	0xCE: defInfos[1],   // adc n
+	0xCF: defInfos[88],  // rst n
+	0xD0: defInfos[74],  // ret cc
+	0xD1: defInfos[69],  // pop rr
+	0xD2: defInfos[37],  // jp cc, nn
+	0xD3: defInfos[30],  // illegal
+	0xD4: defInfos[13],  // call cc, nn
+	0xD5: defInfos[70],  // push rr
+	0xD6: defInfos[103], // sub n
+	0xD7: defInfos[88],  // rst n
+	0xD8: defInfos[74],  // ret cc
+	0xD9: defInfos[75],  // reti
+	0xDA: defInfos[37],  // jp cc, nn
+	0xDB: defInfos[30],  // illegal
+	0xDC: defInfos[13],  // call cc, nn
+	0xDD: defInfos[30],  // illegal
+	0xDE: defInfos[90],  // sbc n
+	0xDF: defInfos[88],  // rst n
+	0xE0: defInfos[62],  // ldh (n), a
+	0xE1: defInfos[69],  // pop rr
+	0xE2: defInfos[61],  // ldh (c), a
+	0xE3: defInfos[30],  // illegal
+	0xE4: defInfos[30],  // illegal
+	0xE5: defInfos[70],  // push rr
+	0xE6: defInfos[9],   // and n
+	0xE7: defInfos[88],  // rst n
+	0xE8: defInfos[7],   // add sp, e
+	0xE9: defInfos[38],  // jp hl
+	0xEA: defInfos[48],  // ld (nn), a
+	0xEB: defInfos[30],  // illegal
+	0xEC: defInfos[30],  // illegal
+	0xED: defInfos[30],  // illegal
+	0xEE: defInfos[108], // xor n
+	0xEF: defInfos[88],  // rst n
+	0xF0: defInfos[64],  // ldh a, (n)
+	0xF1: defInfos[69],  // pop rr
+	0xF2: defInfos[63],  // ldh a, (c)
+	0xF3: defInfos[27],  // di
+	0xF4: defInfos[30],  // illegal
+	0xF5: defInfos[70],  // push rr
+	0xF6: defInfos[67],  // or n
+	0xF7: defInfos[88],  // rst n
+	0xF8: defInfos[55],  // ld hl, sp+e
+	0xF9: defInfos[60],  // ld sp, hl
+	0xFA: defInfos[54],  // ld a, (nn)
+	0xFB: defInfos[28],  // ei
+	0xFC: defInfos[30],  // illegal
+	0xFD: defInfos[30],  // illegal
+	0xFE: defInfos[17],  // cp n
+	0xFF: defInfos[88],  // rst n
+}
+
+var operationsCB = [0x100]Opcode{
+	0x00: defOps[80],  // rlc r
+	0x01: defOps[80],  // rlc r
+	0x02: defOps[80],  // rlc r
+	0x03: defOps[80],  // rlc r
+	0x04: defOps[80],  // rlc r
+	0x05: defOps[80],  // rlc r
+	0x06: defOps[79],  // rlc (hl)
+	0x07: defOps[80],  // rlc r
+	0x08: defOps[86],  // rrc r
+	0x09: defOps[86],  // rrc r
+	0x0A: defOps[86],  // rrc r
+	0x0B: defOps[86],  // rrc r
+	0x0C: defOps[86],  // rrc r
+	0x0D: defOps[86],  // rrc r
+	0x0E: defOps[85],  // rrc (hl)
+	0x0F: defOps[86],  // rrc r
+	0x10: defOps[77],  // rl r
+	0x11: defOps[77],  // rl r
+	0x12: defOps[77],  // rl r
+	0x13: defOps[77],  // rl r
+	0x14: defOps[77],  // rl r
+	0x15: defOps[77],  // rl r
+	0x16: defOps[76],  // rl (hl)
+	0x17: defOps[77],  // rl r
+	0x18: defOps[83],  // rr r
+	0x19: defOps[83],  // rr r
+	0x1A: defOps[83],  // rr r
+	0x1B: defOps[83],  // rr r
+	0x1C: defOps[83],  // rr r
+	0x1D: defOps[83],  // rr r
+	0x1E: defOps[82],  // rr (hl)
+	0x1F: defOps[83],  // rr r
+	0x20: defOps[96],  // sla r
+	0x21: defOps[96],  // sla r
+	0x22: defOps[96],  // sla r
+	0x23: defOps[96],  // sla r
+	0x24: defOps[96],  // sla r
+	0x25: defOps[96],  // sla r
+	0x26: defOps[95],  // sla (hl)
+	0x27: defOps[96],  // sla r
+	0x28: defOps[98],  // sra r
+	0x29: defOps[98],  // sra r
+	0x2A: defOps[98],  // sra r
+	0x2B: defOps[98],  // sra r
+	0x2C: defOps[98],  // sra r
+	0x2D: defOps[98],  // sra r
+	0x2E: defOps[97],  // sra (hl)
+	0x2F: defOps[98],  // sra r
+	0x30: defOps[106], // swap r
+	0x31: defOps[106], // swap r
+	0x32: defOps[106], // swap r
+	0x33: defOps[106], // swap r
+	0x34: defOps[106], // swap r
+	0x35: defOps[106], // swap r
+	0x36: defOps[105], // swap (hl)
+	0x37: defOps[106], // swap r
+	0x38: defOps[100], // srl r
+	0x39: defOps[100], // srl r
+	0x3A: defOps[100], // srl r
+	0x3B: defOps[100], // srl r
+	0x3C: defOps[100], // srl r
+	0x3D: defOps[100], // srl r
+	0x3E: defOps[99],  // srl (hl)
+	0x3F: defOps[100], // srl r
+	0x40: defOps[12],  // bit b, r
+	0x41: defOps[12],  // bit b, r
+	0x42: defOps[12],  // bit b, r
+	0x43: defOps[12],  // bit b, r
+	0x44: defOps[12],  // bit b, r
+	0x45: defOps[12],  // bit b, r
+	0x46: defOps[11],  // bit b, (hl)
+	0x47: defOps[12],  // bit b, r
+	0x48: defOps[12],  // bit b, r
+	0x49: defOps[12],  // bit b, r
+	0x4A: defOps[12],  // bit b, r
+	0x4B: defOps[12],  // bit b, r
+	0x4C: defOps[12],  // bit b, r
+	0x4D: defOps[12],  // bit b, r
+	0x4E: defOps[11],  // bit b, (hl)
+	0x4F: defOps[12],  // bit b, r
+	0x50: defOps[12],  // bit b, r
+	0x51: defOps[12],  // bit b, r
+	0x52: defOps[12],  // bit b, r
+	0x53: defOps[12],  // bit b, r
+	0x54: defOps[12],  // bit b, r
+	0x55: defOps[12],  // bit b, r
+	0x56: defOps[11],  // bit b, (hl)
+	0x57: defOps[12],  // bit b, r
+	0x58: defOps[12],  // bit b, r
+	0x59: defOps[12],  // bit b, r
+	0x5A: defOps[12],  // bit b, r
+	0x5B: defOps[12],  // bit b, r
+	0x5C: defOps[12],  // bit b, r
+	0x5D: defOps[12],  // bit b, r
+	0x5E: defOps[11],  // bit b, (hl)
+	0x5F: defOps[12],  // bit b, r
+	0x60: defOps[12],  // bit b, r
+	0x61: defOps[12],  // bit b, r
+	0x62: defOps[12],  // bit b, r
+	0x63: defOps[12],  // bit b, r
+	0x64: defOps[12],  // bit b, r
+	0x65: defOps[12],  // bit b, r
+	0x66: defOps[11],  // bit b, (hl)
+	0x67: defOps[12],  // bit b, r
+	0x68: defOps[12],  // bit b, r
+	0x69: defOps[12],  // bit b, r
+	0x6A: defOps[12],  // bit b, r
+	0x6B: defOps[12],  // bit b, r
+	0x6C: defOps[12],  // bit b, r
+	0x6D: defOps[12],  // bit b, r
+	0x6E: defOps[11],  // bit b, (hl)
+	0x6F: defOps[12],  // bit b, r
+	0x70: defOps[12],  // bit b, r
+	0x71: defOps[12],  // bit b, r
+	0x72: defOps[12],  // bit b, r
+	0x73: defOps[12],  // bit b, r
+	0x74: defOps[12],  // bit b, r
+	0x75: defOps[12],  // bit b, r
+	0x76: defOps[11],  // bit b, (hl)
+	0x77: defOps[12],  // bit b, r
+	0x78: defOps[12],  // bit b, r
+	0x79: defOps[12],  // bit b, r
+	0x7A: defOps[12],  // bit b, r
+	0x7B: defOps[12],  // bit b, r
+	0x7C: defOps[12],  // bit b, r
+	0x7D: defOps[12],  // bit b, r
+	0x7E: defOps[11],  // bit b, (hl)
+	0x7F: defOps[12],  // bit b, r
+	0x80: defOps[72],  // res b, r
+	0x81: defOps[72],  // res b, r
+	0x82: defOps[72],  // res b, r
+	0x83: defOps[72],  // res b, r
+	0x84: defOps[72],  // res b, r
+	0x85: defOps[72],  // res b, r
+	0x86: defOps[71],  // res b, (hl)
+	0x87: defOps[72],  // res b, r
+	0x88: defOps[72],  // res b, r
+	0x89: defOps[72],  // res b, r
+	0x8A: defOps[72],  // res b, r
+	0x8B: defOps[72],  // res b, r
+	0x8C: defOps[72],  // res b, r
+	0x8D: defOps[72],  // res b, r
+	0x8E: defOps[71],  // res b, (hl)
+	0x8F: defOps[72],  // res b, r
+	0x90: defOps[72],  // res b, r
+	0x91: defOps[72],  // res b, r
+	0x92: defOps[72],  // res b, r
+	0x93: defOps[72],  // res b, r
+	0x94: defOps[72],  // res b, r
+	0x95: defOps[72],  // res b, r
+	0x96: defOps[71],  // res b, (hl)
+	0x97: defOps[72],  // res b, r
+	0x98: defOps[72],  // res b, r
+	0x99: defOps[72],  // res b, r
+	0x9A: defOps[72],  // res b, r
+	0x9B: defOps[72],  // res b, r
+	0x9C: defOps[72],  // res b, r
+	0x9D: defOps[72],  // res b, r
+	0x9E: defOps[71],  // res b, (hl)
+	0x9F: defOps[72],  // res b, r
+	0xA0: defOps[72],  // res b, r
+	0xA1: defOps[72],  // res b, r
+	0xA2: defOps[72],  // res b, r
+	0xA3: defOps[72],  // res b, r
+	0xA4: defOps[72],  // res b, r
+	0xA5: defOps[72],  // res b, r
+	0xA6: defOps[71],  // res b, (hl)
+	0xA7: defOps[72],  // res b, r
+	0xA8: defOps[72],  // res b, r
+	0xA9: defOps[72],  // res b, r
+	0xAA: defOps[72],  // res b, r
+	0xAB: defOps[72],  // res b, r
+	0xAC: defOps[72],  // res b, r
+	0xAD: defOps[72],  // res b, r
+	0xAE: defOps[71],  // res b, (hl)
+	0xAF: defOps[72],  // res b, r
+	0xB0: defOps[72],  // res b, r
+	0xB1: defOps[72],  // res b, r
+	0xB2: defOps[72],  // res b, r
+	0xB3: defOps[72],  // res b, r
+	0xB4: defOps[72],  // res b, r
+	0xB5: defOps[72],  // res b, r
+	0xB6: defOps[71],  // res b, (hl)
+	0xB7: defOps[72],  // res b, r
+	0xB8: defOps[72],  // res b, r
+	0xB9: defOps[72],  // res b, r
+	0xBA: defOps[72],  // res b, r
+	0xBB: defOps[72],  // res b, r
+	0xBC: defOps[72],  // res b, r
+	0xBD: defOps[72],  // res b, r
+	0xBE: defOps[71],  // res b, (hl)
+	0xBF: defOps[72],  // res b, r
+	0xC0: defOps[94],  // set b, r
+	0xC1: defOps[94],  // set b, r
+	0xC2: defOps[94],  // set b, r
+	0xC3: defOps[94],  // set b, r
+	0xC4: defOps[94],  // set b, r
+	0xC5: defOps[94],  // set b, r
+	0xC6: defOps[93],  // set b, (hl)
+	0xC7: defOps[94],  // set b, r
+	0xC8: defOps[94],  // set b, r
+	0xC9: defOps[94],  // set b, r
+	0xCA: defOps[94],  // set b, r
+	0xCB: defOps[94],  // set b, r
+	0xCC: defOps[94],  // set b, r
+	0xCD: defOps[94],  // set b, r
+	0xCE: defOps[93],  // set b, (hl)
+	0xCF: defOps[94],  // set b, r
+	0xD0: defOps[94],  // set b, r
+	0xD1: defOps[94],  // set b, r
+	0xD2: defOps[94],  // set b, r
+	0xD3: defOps[94],  // set b, r
+	0xD4: defOps[94],  // set b, r
+	0xD5: defOps[94],  // set b, r
+	0xD6: defOps[93],  // set b, (hl)
+	0xD7: defOps[94],  // set b, r
+	0xD8: defOps[94],  // set b, r
+	0xD9: defOps[94],  // set b, r
+	0xDA: defOps[94],  // set b, r
+	0xDB: defOps[94],  // set b, r
+	0xDC: defOps[94],  // set b, r
+	0xDD: defOps[94],  // set b, r
+	0xDE: defOps[93],  // set b, (hl)
+	0xDF: defOps[94],  // set b, r
+	0xE0: defOps[94],  // set b, r
+	0xE1: defOps[94],  // set b, r
+	0xE2: defOps[94],  // set b, r
+	0xE3: defOps[94],  // set b, r
+	0xE4: defOps[94],  // set b, r
+	0xE5: defOps[94],  // set b, r
+	0xE6: defOps[93],  // set b, (hl)
+	0xE7: defOps[94],  // set b, r
+	0xE8: defOps[94],  // set b, r
+	0xE9: defOps[94],  // set b, r
+	0xEA: defOps[94],  // set b, r
+	0xEB: defOps[94],  // set b, r
+	0xEC: defOps[94],  // set b, r
+	0xED: defOps[94],  // set b, r
+	0xEE: defOps[93],  // set b, (hl)
+	0xEF: defOps[94],  // set b, r
+	0xF0: defOps[94],  // set b, r
+	0xF1: defOps[94],  // set b, r
+	0xF2: defOps[94],  // set b, r
+	0xF3: defOps[94],  // set b, r
+	0xF4: defOps[94],  // set b, r
+	0xF5: defOps[94],  // set b, r
+	0xF6: defOps[93],  // set b, (hl)
+	0xF7: defOps[94],  // set b, r
+	0xF8: defOps[94],  // set b, r
+	0xF9: defOps[94],  // set b, r
+	0xFA: defOps[94],  // set b, r
+	0xFB: defOps[94],  // set b, r
+	0xFC: defOps[94],  // set b, r
+	0xFD: defOps[94],  // set b, r
+	0xFE: defOps[93],  // set b, (hl)
+	0xFF: defOps[94],  // set b, r
+}
+
+var infosCB = [0x100]Info{
+	0x00: defInfos[80],  // rlc r
+	0x01: defInfos[80],  // rlc r
+	0x02: defInfos[80],  // rlc r
+	0x03: defInfos[80],  // rlc r
+	0x04: defInfos[80],  // rlc r
+	0x05: defInfos[80],  // rlc r
+	0x06: defInfos[79],  // rlc (hl)
+	0x07: defInfos[80],  // rlc r
+	0x08: defInfos[86],  // rrc r
+	0x09: defInfos[86],  // rrc r
+	0x0A: defInfos[86],  // rrc r
+	0x0B: defInfos[86],  // rrc r
+	0x0C: defInfos[86],  // rrc r
+	0x0D: defInfos[86],  // rrc r
+	0x0E: defInfos[85],  // rrc (hl)
+	0x0F: defInfos[86],  // rrc r
+	0x10: defInfos[77],  // rl r
+	0x11: defInfos[77],  // rl r
+	0x12: defInfos[77],  // rl r
+	0x13: defInfos[77],  // rl r
+	0x14: defInfos[77],  // rl r
+	0x15: defInfos[77],  // rl r
+	0x16: defInfos[76],  // rl (hl)
+	0x17: defInfos[77],  // rl r
+	0x18: defInfos[83],  // rr r
+	0x19: defInfos[83],  // rr r
+	0x1A: defInfos[83],  // rr r
+	0x1B: defInfos[83],  // rr r
+	0x1C: defInfos[83],  // rr r
+	0x1D: defInfos[83],  // rr r
+	0x1E: defInfos[82],  // rr (hl)
+	0x1F: defInfos[83],  // rr r
+	0x20: defInfos[96],  // sla r
+	0x21: defInfos[96],  // sla r
+	0x22: defInfos[96],  // sla r
+	0x23: defInfos[96],  // sla r
+	0x24: defInfos[96],  // sla r
+	0x25: defInfos[96],  // sla r
+	0x26: defInfos[95],  // sla (hl)
+	0x27: defInfos[96],  // sla r
+	0x28: defInfos[98],  // sra r
+	0x29: defInfos[98],  // sra r
+	0x2A: defInfos[98],  // sra r
+	0x2B: defInfos[98],  // sra r
+	0x2C: defInfos[98],  // sra r
+	0x2D: defInfos[98],  // sra r
+	0x2E: defInfos[97],  // sra (hl)
+	0x2F: defInfos[98],  // sra r
+	0x30: defInfos[106], // swap r
+	0x31: defInfos[106], // swap r
+	0x32: defInfos[106], // swap r
+	0x33: defInfos[106], // swap r
+	0x34: defInfos[106], // swap r
+	0x35: defInfos[106], // swap r
+	0x36: defInfos[105], // swap (hl)
+	0x37: defInfos[106], // swap r
+	0x38: defInfos[100], // srl r
+	0x39: defInfos[100], // srl r
+	0x3A: defInfos[100], // srl r
+	0x3B: defInfos[100], // srl r
+	0x3C: defInfos[100], // srl r
+	0x3D: defInfos[100], // srl r
+	0x3E: defInfos[99],  // srl (hl)
+	0x3F: defInfos[100], // srl r
+	0x40: defInfos[12],  // bit b, r
+	0x41: defInfos[12],  // bit b, r
+	0x42: defInfos[12],  // bit b, r
+	0x43: defInfos[12],  // bit b, r
+	0x44: defInfos[12],  // bit b, r
+	0x45: defInfos[12],  // bit b, r
+	0x46: defInfos[11],  // bit b, (hl)
+	0x47: defInfos[12],  // bit b, r
+	0x48: defInfos[12],  // bit b, r
+	0x49: defInfos[12],  // bit b, r
+	0x4A: defInfos[12],  // bit b, r
+	0x4B: defInfos[12],  // bit b, r
+	0x4C: defInfos[12],  // bit b, r
+	0x4D: defInfos[12],  // bit b, r
+	0x4E: defInfos[11],  // bit b, (hl)
+	0x4F: defInfos[12],  // bit b, r
+	0x50: defInfos[12],  // bit b, r
+	0x51: defInfos[12],  // bit b, r
+	0x52: defInfos[12],  // bit b, r
+	0x53: defInfos[12],  // bit b, r
+	0x54: defInfos[12],  // bit b, r
+	0x55: defInfos[12],  // bit b, r
+	0x56: defInfos[11],  // bit b, (hl)
+	0x57: defInfos[12],  // bit b, r
+	0x58: defInfos[12],  // bit b, r
+	0x59: defInfos[12],  // bit b, r
+	0x5A: defInfos[12],  // bit b, r
+	0x5B: defInfos[12],  // bit b, r
+	0x5C: defInfos[12],  // bit b, r
+	0x5D: defInfos[12],  // bit b, r
+	0x5E: defInfos[11],  // bit b, (hl)
+	0x5F: defInfos[12],  // bit b, r
+	0x60: defInfos[12],  // bit b, r
+	0x61: defInfos[12],  // bit b, r
+	0x62: defInfos[12],  // bit b, r
+	0x63: defInfos[12],  // bit b, r
+	0x64: defInfos[12],  // bit b, r
+	0x65: defInfos[12],  // bit b, r
+	0x66: defInfos[11],  // bit b, (hl)
+	0x67: defInfos[12],  // bit b, r
+	0x68: defInfos[12],  // bit b, r
+	0x69: defInfos[12],  // bit b, r
+	0x6A: defInfos[12],  // bit b, r
+	0x6B: defInfos[12],  // bit b, r
+	0x6C: defInfos[12],  // bit b, r
+	0x6D: defInfos[12],  // bit b, r
+	0x6E: defInfos[11],  // bit b, (hl)
+	0x6F: defInfos[12],  // bit b, r
+	0x70: defInfos[12],  // bit b, r
+	0x71: defInfos[12],  // bit b, r
+	0x72: defInfos[12],  // bit b, r
+	0x73: defInfos[12],  // bit b, r
+	0x74: defInfos[12],  // bit b, r
+	0x75: defInfos[12],  // bit b, r
+	0x76: defInfos[11],  // bit b, (hl)
+	0x77: defInfos[12],  // bit b, r
+	0x78: defInfos[12],  // bit b, r
+	0x79: defInfos[12],  // bit b, r
+	0x7A: defInfos[12],  // bit b, r
+	0x7B: defInfos[12],  // bit b, r
+	0x7C: defInfos[12],  // bit b, r
+	0x7D: defInfos[12],  // bit b, r
+	0x7E: defInfos[11],  // bit b, (hl)
+	0x7F: defInfos[12],  // bit b, r
+	0x80: defInfos[72],  // res b, r
+	0x81: defInfos[72],  // res b, r
+	0x82: defInfos[72],  // res b, r
+	0x83: defInfos[72],  // res b, r
+	0x84: defInfos[72],  // res b, r
+	0x85: defInfos[72],  // res b, r
+	0x86: defInfos[71],  // res b, (hl)
+	0x87: defInfos[72],  // res b, r
+	0x88: defInfos[72],  // res b, r
+	0x89: defInfos[72],  // res b, r
+	0x8A: defInfos[72],  // res b, r
+	0x8B: defInfos[72],  // res b, r
+	0x8C: defInfos[72],  // res b, r
+	0x8D: defInfos[72],  // res b, r
+	0x8E: defInfos[71],  // res b, (hl)
+	0x8F: defInfos[72],  // res b, r
+	0x90: defInfos[72],  // res b, r
+	0x91: defInfos[72],  // res b, r
+	0x92: defInfos[72],  // res b, r
+	0x93: defInfos[72],  // res b, r
+	0x94: defInfos[72],  // res b, r
+	0x95: defInfos[72],  // res b, r
+	0x96: defInfos[71],  // res b, (hl)
+	0x97: defInfos[72],  // res b, r
+	0x98: defInfos[72],  // res b, r
+	0x99: defInfos[72],  // res b, r
+	0x9A: defInfos[72],  // res b, r
+	0x9B: defInfos[72],  // res b, r
+	0x9C: defInfos[72],  // res b, r
+	0x9D: defInfos[72],  // res b, r
+	0x9E: defInfos[71],  // res b, (hl)
+	0x9F: defInfos[72],  // res b, r
+	0xA0: defInfos[72],  // res b, r
+	0xA1: defInfos[72],  // res b, r
+	0xA2: defInfos[72],  // res b, r
+	0xA3: defInfos[72],  // res b, r
+	0xA4: defInfos[72],  // res b, r
+	0xA5: defInfos[72],  // res b, r
+	0xA6: defInfos[71],  // res b, (hl)
+	0xA7: defInfos[72],  // res b, r
+	0xA8: defInfos[72],  // res b, r
+	0xA9: defInfos[72],  // res b, r
+	0xAA: defInfos[72],  // res b, r
+	0xAB: defInfos[72],  // res b, r
+	0xAC: defInfos[72],  // res b, r
+	0xAD: defInfos[72],  // res b, r
+	0xAE: defInfos[71],  // res b, (hl)
+	0xAF: defInfos[72],  // res b, r
+	0xB0: defInfos[72],  // res b, r
+	0xB1: defInfos[72],  // res b, r
+	0xB2: defInfos[72],  // res b, r
+	0xB3: defInfos[72],  // res b, r
+	0xB4: defInfos[72],  // res b, r
+	0xB5: defInfos[72],  // res b, r
+	0xB6: defInfos[71],  // res b, (hl)
+	0xB7: defInfos[72],  // res b, r
+	0xB8: defInfos[72],  // res b, r
+	0xB9: defInfos[72],  // res b, r
+	0xBA: defInfos[72],  // res b, r
+	0xBB: defInfos[72],  // res b, r
+	0xBC: defInfos[72],  // res b, r
+	0xBD: defInfos[72],  // res b, r
+	0xBE: defInfos[71],  // res b, (hl)
+	0xBF: defInfos[72],  // res b, r
+	0xC0: defInfos[94],  // set b, r
+	0xC1: defInfos[94],  // set b, r
+	0xC2: defInfos[94],  // set b, r
+	0xC3: defInfos[94],  // set b, r
+	0xC4: defInfos[94],  // set b, r
+	0xC5: defInfos[94],  // set b, r
+	0xC6: defInfos[93],  // set b, (hl)
+	0xC7: defInfos[94],  // set b, r
+	0xC8: defInfos[94],  // set b, r
+	0xC9: defInfos[94],  // set b, r
+	0xCA: defInfos[94],  // set b, r
+	0xCB: defInfos[94],  // set b, r
+	0xCC: defInfos[94],  // set b, r
+	0xCD: defInfos[94],  // set b, r
+	0xCE: defInfos[93],  // set b, (hl)
+	0xCF: defInfos[94],  // set b, r
+	0xD0: defInfos[94],  // set b, r
+	0xD1: defInfos[94],  // set b, r
+	0xD2: defInfos[94],  // set b, r
+	0xD3: defInfos[94],  // set b, r
+	0xD4: defInfos[94],  // set b, r
+	0xD5: defInfos[94],  // set b, r
+	0xD6: defInfos[93],  // set b, (hl)
+	0xD7: defInfos[94],  // set b, r
+	0xD8: defInfos[94],  // set b, r
+	0xD9: defInfos[94],  // set b, r
+	0xDA: defInfos[94],  // set b, r
+	0xDB: defInfos[94],  // set b, r
+	0xDC: defInfos[94],  // set b, r
+	0xDD: defInfos[94],  // set b, r
+	0xDE: defInfos[93],  // set b, (hl)
+	0xDF: defInfos[94],  // set b, r
+	0xE0: defInfos[94],  // set b, r
+	0xE1: defInfos[94],  // set b, r
+	0xE2: defInfos[94],  // set b, r
+	0xE3: defInfos[94],  // set b, r
+	0xE4: defInfos[94],  // set b, r
+	0xE5: defInfos[94],  // set b, r
+	0xE6: defInfos[93],  // set b, (hl)
+	0xE7: defInfos[94],  // set b, r
+	0xE8: defInfos[94],  // set b, r
+	0xE9: defInfos[94],  // set b, r
+	0xEA: defInfos[94],  // set b, r
+	0xEB: defInfos[94],  // set b, r
+	0xEC: defInfos[94],  // set b, r
+	0xED: defInfos[94],  // set b, r
+	0xEE: defInfos[93],  // set b, (hl)
+	0xEF: defInfos[94],  // set b, r
+	0xF0: defInfos[94],  // set b, r
+	0xF1: defInfos[94],  // set b, r
+	0xF2: defInfos[94],  // set b, r
+	0xF3: defInfos[94],  // set b, r
+	0xF4: defInfos[94],  // set b, r
+	0xF5: defInfos[94],  // set b, r
+	0xF6: defInfos[93],  // set b, (hl)
+	0xF7: defInfos[94],  // set b, r
+	0xF8: defInfos[94],  // set b, r
+	0xF9: defInfos[94],  // set b, r
+	0xFA: defInfos[94],  // set b, r
+	0xFB: defInfos[94],  // set b, r
+	0xFC: defInfos[94],  // set b, r
+	0xFD: defInfos[94],  // set b, r
+	0xFE: defInfos[93],  // set b, (hl)
+	0xFF: defInfos[94],  // set b, r
+}
@@ -0,0 +1,30 @@
+package cpu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGeneratedTables checks the generated opcode tables are up to date with the defs.
+// If it fails, run go generate.
+func TestGeneratedTables(t *testing.T) {
+	is, err := LoadInstructionSet(DefFS())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, *is.ops, operations)
+	assert.Equal(t, *is.opsCB, operationsCB)
+	assert.Equal(t, *is.infos, infos)
+	assert.Equal(t, *is.infosCB, infosCB)
+}
+
+func TestOpByName(t *testing.T) {
+	var alu ALUOp
+	assert.NoError(t, OpByName(&alu, ALUOp(1).String()))
+	assert.Equal(t, ALUOp(1), alu)
+	assert.NoError(t, OpByName(&alu, ""))
+	assert.Zero(t, alu)
+	assert.EqualError(t, OpByName(&alu, "nope"), `unknown cpu.ALUOp "nope"`)
+}
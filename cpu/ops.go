@@ -16,7 +16,7 @@ const (
 	AddrWZ                            // WZ
 )
 
-// R16 returns the equivalent R16 value, or panics if invalid (e.g. a non-register address).
+// R16 returns the equivalent R16 value, or panics with InvalidOpError if invalid (e.g. a non-register address).
 func (op AddrSelector) R16() R16 {
 	switch op {
 	case AddrBC:
@@ -32,7 +32,7 @@ func (op AddrSelector) R16() R16 {
 	case AddrWZ:
 		return WZ
 	}
-	panic(op)
+	panic(InvalidOpError{Op: op})
 }
 
 // Do resolves the op to an explicit address bus value.
@@ -128,38 +128,25 @@ const (
 	Dec                     // --
 	Set_SP                  // SP ←
 	IncSetPC
-	IRQ
 )
 
 // Do performs the IDU op, returning the new state.
 func (op IDUOp) Do(s State, addr AddrSelector) State {
 	switch op {
 	case 0:
-	case Inc, IncSetPC:
+	case Inc:
 		rr := addr.R16()
 		s.R16Set(rr, s.R16(rr)+1)
-		if op == IncSetPC {
-			s.PC = s.R16(rr)
-		}
+	case IncSetPC:
+		// the incremented address goes to PC, leaving the register on the bus as it was
+		s.PC = s.R16(addr.R16()) + 1
 	case Dec:
 		rr := addr.R16()
 		s.R16Set(rr, s.R16(rr)-1)
 	case Set_SP:
 		s.SP = s.R16(addr.R16())
-	case IRQ:
-		r := s.IF & s.IE
-		if r&0b11111 == 0 {
-			panic("IRQ 0")
-		}
-		for i := range uint8(5) {
-			if r&(1<<i) != 0 {
-				s.PC = 0x0040 + 0x8*uint16(i)
-				s.IF &= ^uint8(1 << i)
-				break
-			}
-		}
 	default:
-		panic(op)
+		panic(InvalidOpError{Op: op})
 	}
 
 	return s
@@ -399,7 +386,7 @@ func (op ALUOp) Do(s State, opcode uint8) State {
 	case W_Equals_res:
 		s.W = s.ALUResult
 	default:
-		panic(op)
+		panic(InvalidOpError{Op: op})
 	}
 
 	return s
@@ -416,10 +403,12 @@ const (
 	RRstk_Equals_WZ                        // rrstk ← WZ
 	Set_IME                                // IME ← 1
 	Reset_IME                              // IME ← 0
-	Panic                                  // PANIC
+	Stop                                   // STOP
 	Set_CB                                 // CB ← 1
 	Halt                                   // HALT
 	Cond                                   // COND
+	IRQ                                    // WZ ← IRQ vector
+	Lock                                   // LOCK
 )
 
 // Do performs the MISC op, returning the new state.
@@ -440,36 +429,68 @@ func (op MiscOp) Do(s State, opcode uint8) State {
 	case RRstk_Equals_WZ:
 		s.R16Set(rrstkToR16((opcode>>4)&0b11), mk16(s.W, s.Z))
 	case Set_IME:
-		s.IME = true
+		// EI is delayed: IME is only set after the following instruction
+		s.IMEPending = true
 	case Reset_IME:
 		s.IME = false
-	case Panic:
-		panic("MiscOp: Panic")
+		s.IMEPending = false
+	case Stop:
+		s.Signals |= DIVReset
+		if s.SpeedSwitch {
+			// armed via KEY1: switch speed instead of entering stop mode
+			s.SpeedSwitch = false
+			s.DoubleSpeed = !s.DoubleSpeed
+			s.Signals |= SpeedSwitched
+		} else {
+			s.Stopped = true
+		}
 	case Set_CB:
 		s.CB = true
 	case Halt:
-		s.Halted = true
+		if !s.IME && s.Pending() != 0 {
+			// HALT bug: with an interrupt already pending, HALT exits
+			// immediately but the PC fails to increment for the fetch,
+			// so the next byte is read twice.
+			s.PC--
+		} else {
+			s.Halted = true
+		}
 	case Cond:
 		if Condition((opcode >> 3) & 0b11).Test(s.F) {
 			s.S++
 		}
+	case Lock:
+		s.Locked = true
+		s.Signals |= LockedUp
+	case IRQ:
+		// select the highest priority pending interrupt & acknowledge it.
+		// if none is pending anymore, the dispatch is cancelled and jumps to $0000.
+		s.W, s.Z = 0x00, 0x00
+		r := s.Pending()
+		for i := range uint8(5) {
+			if r&(1<<i) != 0 {
+				s.Z = 0x40 + 0x8*i
+				s.IF &= ^uint8(1 << i)
+				break
+			}
+		}
 	default:
-		panic(op)
+		panic(InvalidOpError{Op: op})
 	}
 	return s
 }
 
 // rToR8 converts the R part of an opcode param to an R8.
-// panics if v is not in [0, 1, 2, 3, 4, 5, 7].
+// panics with InvalidRegisterError if v is not in [0, 1, 2, 3, 4, 5, 7].
 func rToR8(v uint8) R8 {
 	if v == 6 || v > 7 {
-		panic(v)
+		panic(InvalidRegisterError{Register: v})
 	}
 	return R8(v)
 }
 
 // rrToR16 converts the RR part of an opcode param to an R16.
-// panics if v is not in [0, 1, 2, 3].
+// panics with InvalidRegisterError if v is not in [0, 1, 2, 3].
 func rrToR16(v uint8) R16 {
 	switch v {
 	case 0:
@@ -481,11 +502,11 @@ func rrToR16(v uint8) R16 {
 	case 3:
 		return SP
 	}
-	panic(v)
+	panic(InvalidRegisterError{Register: v})
 }
 
 // rrstkToR16 converts the rrstk part of an opcode param to an R16.
-// panics if v is not in [0, 1, 2, 3].
+// panics with InvalidRegisterError if v is not in [0, 1, 2, 3].
 func rrstkToR16(v uint8) R16 {
 	switch v {
 	case 0:
@@ -497,5 +518,5 @@ func rrstkToR16(v uint8) R16 {
 	case 3:
 		return AF
 	}
-	panic(v)
+	panic(InvalidRegisterError{Register: v})
 }
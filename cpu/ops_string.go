@@ -145,12 +145,11 @@ func _() {
 	_ = x[Dec-2]
 	_ = x[Set_SP-3]
 	_ = x[IncSetPC-4]
-	_ = x[IRQ-5]
 }
 
-const _IDUOp_name = "++--SP ←IncSetPCIRQ"
+const _IDUOp_name = "++--SP ←IncSetPC"
 
-var _IDUOp_index = [...]uint8{0, 2, 4, 10, 18, 21}
+var _IDUOp_index = [...]uint8{0, 2, 4, 10, 18}
 
 func (i IDUOp) String() string {
 	i -= 1
@@ -171,15 +170,17 @@ func _() {
 	_ = x[RRstk_Equals_WZ-6]
 	_ = x[Set_IME-7]
 	_ = x[Reset_IME-8]
-	_ = x[Panic-9]
+	_ = x[Stop-9]
 	_ = x[Set_CB-10]
 	_ = x[Halt-11]
 	_ = x[Cond-12]
+	_ = x[IRQ-13]
+	_ = x[Lock-14]
 }
 
-const _MiscOp_name = "PC ← WZSP ← WZPC ← WZ, IME ← 1PC ← addrrr ← WZrrstk ← WZIME ← 1IME ← 0PANICCB ← 1HALTCOND"
+const _MiscOp_name = "PC ← WZSP ← WZPC ← WZ, IME ← 1PC ← addrrr ← WZrrstk ← WZIME ← 1IME ← 0STOPCB ← 1HALTCONDWZ ← IRQ vectorLOCK"
 
-var _MiscOp_index = [...]uint8{0, 9, 18, 38, 49, 58, 70, 79, 88, 93, 101, 105, 109}
+var _MiscOp_index = [...]uint8{0, 9, 18, 38, 49, 58, 70, 79, 88, 92, 100, 104, 108, 125, 129}
 
 func (i MiscOp) String() string {
 	i -= 1
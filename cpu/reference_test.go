@@ -0,0 +1,506 @@
+package cpu
+
+// refMachine is a straightforward instruction-level SM83 interpreter, written
+// from the documented behaviour of each instruction rather than from the
+// micro-ops, to check the CPU against.
+type refMachine struct {
+	A, F, B, C, D, E, H, L uint8
+	SP, PC                 uint16 // PC is the address of the next instruction
+	IME, IMEPending        bool
+	IE, IF                 uint8
+	Halted, Stopped        bool
+	Locked                 bool
+
+	mem    refMemory
+	writes []Access
+}
+
+// refMemory is the memory a refMachine runs on.
+type refMemory interface {
+	Read(addr uint16) uint8
+	Write(addr uint16, v uint8)
+}
+
+func (m *refMachine) read(addr uint16) uint8 {
+	switch addr {
+	case 0xFF0F:
+		return m.IF | 0xE0
+	case 0xFFFF:
+		return m.IE
+	}
+	return m.mem.Read(addr)
+}
+
+func (m *refMachine) write(addr uint16, v uint8) {
+	m.writes = append(m.writes, Access{Addr: addr, Data: v, Write: true})
+	switch addr {
+	case 0xFF0F:
+		m.IF = v & 0x1F
+	case 0xFFFF:
+		m.IE = v
+	default:
+		m.mem.Write(addr, v)
+	}
+}
+
+func (m *refMachine) imm8() uint8 {
+	v := m.read(m.PC)
+	m.PC++
+	return v
+}
+
+func (m *refMachine) imm16() uint16 {
+	lo := m.imm8()
+	return uint16(m.imm8())<<8 | uint16(lo)
+}
+
+func (m *refMachine) push(v uint16) {
+	m.SP--
+	m.write(m.SP, uint8(v>>8))
+	m.SP--
+	m.write(m.SP, uint8(v))
+}
+
+func (m *refMachine) pop() uint16 {
+	lo := m.read(m.SP)
+	m.SP++
+	hi := m.read(m.SP)
+	m.SP++
+	return uint16(hi)<<8 | uint16(lo)
+}
+
+// flags sets F from the individual flags.
+func (m *refMachine) flags(z, n, h, c bool) {
+	m.F = 0
+	for i, set := range []bool{z, n, h, c} {
+		if set {
+			m.F |= 0x80 >> i
+		}
+	}
+}
+
+func (m *refMachine) zf() bool { return m.F&0x80 != 0 }
+func (m *refMachine) nf() bool { return m.F&0x40 != 0 }
+func (m *refMachine) hf() bool { return m.F&0x20 != 0 }
+func (m *refMachine) cf() bool { return m.F&0x10 != 0 }
+
+// reg returns the 8-bit register (or memory at HL, for 6) selected by a 3-bit field.
+func (m *refMachine) reg(i uint8) uint8 {
+	switch i {
+	case 0:
+		return m.B
+	case 1:
+		return m.C
+	case 2:
+		return m.D
+	case 3:
+		return m.E
+	case 4:
+		return m.H
+	case 5:
+		return m.L
+	case 6:
+		return m.read(m.hl())
+	default:
+		return m.A
+	}
+}
+
+func (m *refMachine) setReg(i uint8, v uint8) {
+	switch i {
+	case 0:
+		m.B = v
+	case 1:
+		m.C = v
+	case 2:
+		m.D = v
+	case 3:
+		m.E = v
+	case 4:
+		m.H = v
+	case 5:
+		m.L = v
+	case 6:
+		m.write(m.hl(), v)
+	default:
+		m.A = v
+	}
+}
+
+func (m *refMachine) hl() uint16 { return uint16(m.H)<<8 | uint16(m.L) }
+
+// pair returns the register pair selected by a 2-bit field; AF replaces SP if stack is set.
+func (m *refMachine) pair(i uint8, stack bool) uint16 {
+	switch i {
+	case 0:
+		return uint16(m.B)<<8 | uint16(m.C)
+	case 1:
+		return uint16(m.D)<<8 | uint16(m.E)
+	case 2:
+		return m.hl()
+	default:
+		if stack {
+			return uint16(m.A)<<8 | uint16(m.F)
+		}
+		return m.SP
+	}
+}
+
+func (m *refMachine) setPair(i uint8, stack bool, v uint16) {
+	hi, lo := uint8(v>>8), uint8(v)
+	switch i {
+	case 0:
+		m.B, m.C = hi, lo
+	case 1:
+		m.D, m.E = hi, lo
+	case 2:
+		m.H, m.L = hi, lo
+	default:
+		if stack {
+			m.A, m.F = hi, lo&0xF0
+		} else {
+			m.SP = v
+		}
+	}
+}
+
+// cond tests the condition selected by a 2-bit field: NZ, Z, NC, C.
+func (m *refMachine) cond(i uint8) bool {
+	switch i {
+	case 0:
+		return !m.zf()
+	case 1:
+		return m.zf()
+	case 2:
+		return !m.cf()
+	default:
+		return m.cf()
+	}
+}
+
+// alu performs one of the 8 accumulator operations: ADD ADC SUB SBC AND XOR OR CP.
+func (m *refMachine) alu(op uint8, v uint8) {
+	a, carry := int(m.A), 0
+	if m.cf() && (op == 1 || op == 3) {
+		carry = 1
+	}
+	switch op {
+	case 0, 1:
+		res := a + int(v) + carry
+		m.flags(uint8(res) == 0, false, a&0xF+int(v&0xF)+carry > 0xF, res > 0xFF)
+		m.A = uint8(res)
+	case 2, 3, 7:
+		res := a - int(v) - carry
+		m.flags(uint8(res) == 0, true, a&0xF-int(v&0xF)-carry < 0, res < 0)
+		if op != 7 {
+			m.A = uint8(res)
+		}
+	case 4:
+		m.A &= v
+		m.flags(m.A == 0, false, true, false)
+	case 5:
+		m.A ^= v
+		m.flags(m.A == 0, false, false, false)
+	case 6:
+		m.A |= v
+		m.flags(m.A == 0, false, false, false)
+	}
+}
+
+// rot performs one of the 8 CB rotates & shifts: RLC RRC RL RR SLA SRA SWAP SRL.
+func (m *refMachine) rot(op uint8, v uint8) uint8 {
+	var res uint8
+	var carry bool
+	switch op {
+	case 0:
+		res, carry = v<<1|v>>7, v&0x80 != 0
+	case 1:
+		res, carry = v>>1|v<<7, v&1 != 0
+	case 2:
+		res, carry = v<<1, v&0x80 != 0
+		if m.cf() {
+			res |= 1
+		}
+	case 3:
+		res, carry = v>>1, v&1 != 0
+		if m.cf() {
+			res |= 0x80
+		}
+	case 4:
+		res, carry = v<<1, v&0x80 != 0
+	case 5:
+		res, carry = v>>1|v&0x80, v&1 != 0
+	case 6:
+		res = v<<4 | v>>4
+	case 7:
+		res, carry = v>>1, v&1 != 0
+	}
+	m.flags(res == 0, false, false, carry)
+	return res
+}
+
+// addSP returns SP plus a signed immediate, setting the flags as ADD SP, e does.
+func (m *refMachine) addSP() uint16 {
+	e := m.imm8()
+	m.flags(false, false, m.SP&0xF+uint16(e&0xF) > 0xF, m.SP&0xFF+uint16(e) > 0xFF)
+	return m.SP + uint16(int8(e))
+}
+
+// Step runs an instruction, returning the M-cycles it took.
+func (m *refMachine) Step() (cycles int) {
+	imePending := m.IMEPending
+	defer func() {
+		// EI takes effect after the following instruction completes, which
+		// STOP & illegal opcodes don't (the instruction after STOP does instead)
+		if imePending && m.IMEPending && !m.Stopped && !m.Locked {
+			m.IME, m.IMEPending = true, false
+		}
+	}()
+
+	pc := m.PC
+	op := m.imm8()
+	x, y, z := op>>6, (op>>3)&7, op&7
+	p, q := y>>1, y&1
+
+	switch {
+	case op == 0x00: // NOP
+		return 1
+	case op == 0x08: // LD (nn), SP
+		nn := m.imm16()
+		m.write(nn, uint8(m.SP))
+		m.write(nn+1, uint8(m.SP>>8))
+		return 5
+	case op == 0x10: // STOP
+		m.PC++
+		m.Stopped = true
+		return 1
+	case op == 0x18: // JR e
+		e := int8(m.imm8())
+		m.PC += uint16(e)
+		return 3
+	case x == 0 && z == 0 && y >= 4: // JR cc, e
+		e := int8(m.imm8())
+		if m.cond(y - 4) {
+			m.PC += uint16(e)
+			return 3
+		}
+		return 2
+	case x == 0 && z == 1 && q == 0: // LD rr, nn
+		m.setPair(p, false, m.imm16())
+		return 3
+	case x == 0 && z == 1: // ADD HL, rr
+		hl, rr := m.hl(), m.pair(p, false)
+		m.flags(m.zf(), false, hl&0xFFF+rr&0xFFF > 0xFFF, uint32(hl)+uint32(rr) > 0xFFFF)
+		m.setPair(2, false, hl+rr)
+		return 2
+	case x == 0 && z == 2: // LD (rr), A & LD A, (rr)
+		var addr uint16
+		switch p {
+		case 0, 1:
+			addr = m.pair(p, false)
+		case 2:
+			addr = m.hl()
+			m.setPair(2, false, addr+1)
+		case 3:
+			addr = m.hl()
+			m.setPair(2, false, addr-1)
+		}
+		if q == 0 {
+			m.write(addr, m.A)
+		} else {
+			m.A = m.read(addr)
+		}
+		return 2
+	case x == 0 && z == 3: // INC rr & DEC rr
+		if q == 0 {
+			m.setPair(p, false, m.pair(p, false)+1)
+		} else {
+			m.setPair(p, false, m.pair(p, false)-1)
+		}
+		return 2
+	case x == 0 && (z == 4 || z == 5): // INC r & DEC r
+		v := m.reg(y)
+		if z == 4 {
+			m.flags(v+1 == 0, false, v&0xF == 0xF, m.cf())
+			m.setReg(y, v+1)
+		} else {
+			m.flags(v-1 == 0, true, v&0xF == 0, m.cf())
+			m.setReg(y, v-1)
+		}
+		if y == 6 {
+			return 3
+		}
+		return 1
+	case x == 0 && z == 6: // LD r, n
+		m.setReg(y, m.imm8())
+		if y == 6 {
+			return 3
+		}
+		return 2
+	case x == 0 && z == 7:
+		switch y {
+		case 0, 1, 2, 3: // RLCA RRCA RLA RRA
+			m.A = m.rot(y, m.A)
+			m.F &^= 0x80
+		case 4: // DAA
+			a, c := int(m.A), m.cf()
+			if !m.nf() {
+				if c || a > 0x99 {
+					a += 0x60
+					c = true
+				}
+				if m.hf() || a&0xF > 0x9 {
+					a += 0x06
+				}
+			} else {
+				if c {
+					a -= 0x60
+				}
+				if m.hf() {
+					a -= 0x06
+				}
+			}
+			m.A = uint8(a)
+			m.flags(m.A == 0, m.nf(), false, c)
+		case 5: // CPL
+			m.A = ^m.A
+			m.flags(m.zf(), true, true, m.cf())
+		case 6: // SCF
+			m.flags(m.zf(), false, false, true)
+		case 7: // CCF
+			m.flags(m.zf(), false, false, !m.cf())
+		}
+		return 1
+	case op == 0x76: // HALT
+		m.Halted = true
+		return 1
+	case x == 1: // LD r, r'
+		m.setReg(y, m.reg(z))
+		if y == 6 || z == 6 {
+			return 2
+		}
+		return 1
+	case x == 2: // ALU A, r
+		m.alu(y, m.reg(z))
+		if z == 6 {
+			return 2
+		}
+		return 1
+	case x == 3 && z == 0 && y < 4: // RET cc
+		if m.cond(y) {
+			m.PC = m.pop()
+			return 5
+		}
+		return 2
+	case op == 0xE0: // LDH (n), A
+		m.write(0xFF00+uint16(m.imm8()), m.A)
+		return 3
+	case op == 0xF0: // LDH A, (n)
+		m.A = m.read(0xFF00 + uint16(m.imm8()))
+		return 3
+	case op == 0xE8: // ADD SP, e
+		m.SP = m.addSP()
+		return 4
+	case op == 0xF8: // LD HL, SP+e
+		m.setPair(2, false, m.addSP())
+		return 3
+	case x == 3 && z == 1 && q == 0: // POP rr
+		m.setPair(p, true, m.pop())
+		return 3
+	case op == 0xC9: // RET
+		m.PC = m.pop()
+		return 4
+	case op == 0xD9: // RETI
+		m.PC = m.pop()
+		m.IME = true
+		return 4
+	case op == 0xE9: // JP HL
+		m.PC = m.hl()
+		return 1
+	case op == 0xF9: // LD SP, HL
+		m.SP = m.hl()
+		return 2
+	case x == 3 && z == 2 && y < 4: // JP cc, nn
+		nn := m.imm16()
+		if m.cond(y) {
+			m.PC = nn
+			return 4
+		}
+		return 3
+	case op == 0xE2: // LDH (C), A
+		m.write(0xFF00+uint16(m.C), m.A)
+		return 2
+	case op == 0xF2: // LDH A, (C)
+		m.A = m.read(0xFF00 + uint16(m.C))
+		return 2
+	case op == 0xEA: // LD (nn), A
+		m.write(m.imm16(), m.A)
+		return 4
+	case op == 0xFA: // LD A, (nn)
+		m.A = m.read(m.imm16())
+		return 4
+	case op == 0xC3: // JP nn
+		m.PC = m.imm16()
+		return 4
+	case op == 0xCB:
+		return m.stepCB()
+	case op == 0xF3: // DI
+		m.IME, m.IMEPending = false, false
+		return 1
+	case op == 0xFB: // EI
+		m.IMEPending = true
+		return 1
+	case x == 3 && z == 4 && y < 4: // CALL cc, nn
+		nn := m.imm16()
+		if m.cond(y) {
+			m.push(m.PC)
+			m.PC = nn
+			return 6
+		}
+		return 3
+	case x == 3 && z == 5 && q == 0: // PUSH rr
+		m.push(m.pair(p, true))
+		return 4
+	case op == 0xCD: // CALL nn
+		nn := m.imm16()
+		m.push(m.PC)
+		m.PC = nn
+		return 6
+	case x == 3 && z == 6: // ALU A, n
+		m.alu(y, m.imm8())
+		return 2
+	case x == 3 && z == 7: // RST n
+		m.push(m.PC)
+		m.PC = uint16(y) * 8
+		return 4
+	default: // illegal: the CPU locks up
+		m.PC = pc
+		m.Locked = true
+		return 1
+	}
+}
+
+func (m *refMachine) stepCB() int {
+	op := m.imm8()
+	x, y, z := op>>6, (op>>3)&7, op&7
+
+	cycles := 2
+	if z == 6 {
+		cycles = 4
+	}
+	v := m.reg(z)
+	switch x {
+	case 0: // rotates & shifts
+		m.setReg(z, m.rot(y, v))
+	case 1: // BIT
+		m.flags(v&(1<<y) == 0, false, true, m.cf())
+		if z == 6 {
+			cycles = 3
+		}
+	case 2: // RES
+		m.setReg(z, v&^(1<<y))
+	case 3: // SET
+		m.setReg(z, v|1<<y)
+	}
+	return cycles
+}
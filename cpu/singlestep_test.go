@@ -0,0 +1,246 @@
+package cpu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// singleStepDir is where the SingleStepTests SM83 vectors (one JSON file per
+// opcode, e.g. "00.json" & "cb 46.json") are read from. The SM83_TESTS
+// environment variable points it at a full copy of the suite.
+const singleStepDir = "testdata/sm83"
+
+// sstTest is a single-instruction test vector.
+type sstTest struct {
+	Name    string     `json:"name"`
+	Initial sstState   `json:"initial"`
+	Final   sstState   `json:"final"`
+	Cycles  []sstCycle `json:"cycles"`
+}
+
+// sstState is a CPU state & the memory around it.
+type sstState struct {
+	A, B, C, D, E, F, H, L uint8
+	PC, SP                 uint16
+	IME                    uint8
+	IE                     *uint8 // optional
+	EI                     *uint8 // optional: an EI is pending
+	RAM                    [][2]uint16
+}
+
+// sstCycle is the bus activity of an M-cycle: [address, data, pins], where
+// pins is "rwm" with '-' for each line that isn't active. The data of
+// internal cycles is null.
+type sstCycle struct {
+	Addr              uint16
+	Data              *uint8
+	Read, Write, Mreq bool
+}
+
+func (c *sstCycle) UnmarshalJSON(b []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 3 {
+		return fmt.Errorf("cycle %s: want [address, data, pins]", b)
+	}
+	var pins string
+	if err := errors.Join(
+		json.Unmarshal(raw[0], &c.Addr),
+		json.Unmarshal(raw[1], &c.Data),
+		json.Unmarshal(raw[2], &pins),
+	); err != nil {
+		return fmt.Errorf("cycle %s: %w", b, err)
+	}
+	if len(pins) != 3 {
+		return fmt.Errorf("cycle %s: bad pins %q", b, pins)
+	}
+	c.Read, c.Write, c.Mreq = pins[0] == 'r', pins[1] == 'w', pins[2] == 'm'
+	return nil
+}
+
+func (c sstCycle) pins() string {
+	pins := []byte("---")
+	if c.Read {
+		pins[0] = 'r'
+	}
+	if c.Write {
+		pins[1] = 'w'
+	}
+	if c.Mreq {
+		pins[2] = 'm'
+	}
+	return string(pins)
+}
+
+// runSingleStep runs a vector's cycles through NextCycle, StartCycle &
+// FinishCycle on a flat memory, returning the first divergence from it.
+// Addresses & data are only checked on cycles that access memory, as what
+// the SM83 drives onto the bus during internal cycles isn't modelled.
+func runSingleStep(test sstTest) (err error) {
+	init := test.Initial
+	mem := map[uint16]uint8{}
+	for _, kv := range init.RAM {
+		mem[kv[0]] = uint8(kv[1])
+	}
+
+	// the vectors start with the opcode already fetched
+	s := State{
+		A: init.A, B: init.B, C: init.C, D: init.D, E: init.E, F: init.F, H: init.H, L: init.L,
+		PC: init.PC, SP: init.SP,
+		IME: init.IME != 0,
+		IR:  mem[init.PC-1],
+	}
+	if init.IE != nil {
+		s.IE = *init.IE
+	}
+	if init.EI != nil {
+		s.IMEPending = *init.EI != 0
+	}
+
+	cycleNo := 0
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cycle %d: %v", cycleNo, r)
+		}
+	}()
+
+	for i, want := range test.Cycles {
+		cycleNo = i
+		var cycle Cycle
+		var got sstCycle
+		if s, cycle = NextCycle(s); !s.Idle() {
+			s, cycle = StartCycle(s, cycle)
+			addr := cycle.Addr.Do(s)
+			var data uint8
+			if cycle.Data.RD() {
+				data = mem[addr]
+				got = sstCycle{Addr: addr, Data: &data, Read: true, Mreq: true}
+			} else if wr, v := cycle.Data.WR(s, s.IR); wr {
+				mem[addr] = v
+				got = sstCycle{Addr: addr, Data: &v, Write: true, Mreq: true}
+			}
+			s = FinishCycle(s, cycle, data)
+		}
+
+		switch {
+		case got.pins() != want.pins():
+			return fmt.Errorf("cycle %d: pins: want %s, got %s", i, want.pins(), got.pins())
+		case !got.Mreq:
+		case got.Addr != want.Addr:
+			return fmt.Errorf("cycle %d: address: want $%04X, got $%04X", i, want.Addr, got.Addr)
+		case want.Data != nil && *got.Data != *want.Data:
+			return fmt.Errorf("cycle %d: data: want $%02X, got $%02X", i, *want.Data, *got.Data)
+		}
+	}
+
+	if s.S != 0 || s.Interrupting {
+		return fmt.Errorf("instruction still running after %d cycles", len(test.Cycles))
+	}
+
+	final := test.Final
+	for _, r := range []struct {
+		name      string
+		want, got uint16
+	}{
+		{"A", uint16(final.A), uint16(s.A)},
+		{"F", uint16(final.F), uint16(s.F)},
+		{"B", uint16(final.B), uint16(s.B)},
+		{"C", uint16(final.C), uint16(s.C)},
+		{"D", uint16(final.D), uint16(s.D)},
+		{"E", uint16(final.E), uint16(s.E)},
+		{"H", uint16(final.H), uint16(s.H)},
+		{"L", uint16(final.L), uint16(s.L)},
+		{"SP", final.SP, s.SP},
+		{"PC", final.PC, s.PC},
+	} {
+		if r.want != r.got {
+			return fmt.Errorf("final %s: want $%02X, got $%02X", r.name, r.want, r.got)
+		}
+	}
+	if ime := final.IME != 0; ime != s.IME {
+		return fmt.Errorf("final IME: want %t, got %t", ime, s.IME)
+	}
+	if final.IE != nil && *final.IE != s.IE {
+		return fmt.Errorf("final IE: want $%02X, got $%02X", *final.IE, s.IE)
+	}
+	if final.EI != nil && (*final.EI != 0) != s.IMEPending {
+		return fmt.Errorf("final EI pending: want %t, got %t", *final.EI != 0, s.IMEPending)
+	}
+	for _, kv := range final.RAM {
+		if got := mem[kv[0]]; got != uint8(kv[1]) {
+			return fmt.Errorf("final ($%04X): want $%02X, got $%02X", kv[0], kv[1], got)
+		}
+	}
+	return nil
+}
+
+// TestSingleStep runs the SingleStepTests SM83 vectors found in singleStepDir,
+// reporting the first divergence for each opcode.
+func TestSingleStep(t *testing.T) {
+	dir := singleStepDir
+	if env := os.Getenv("SM83_TESTS"); env != "" {
+		dir = env
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	if len(files) == 0 {
+		t.Skipf("no test vectors in %s", dir)
+	}
+
+	for _, file := range files {
+		opcode := strings.TrimSuffix(filepath.Base(file), ".json")
+		t.Run(opcode, func(t *testing.T) {
+			b, err := os.ReadFile(file)
+			require.NoError(t, err)
+			var tests []sstTest
+			require.NoError(t, json.Unmarshal(b, &tests))
+
+			for _, test := range tests {
+				if err := runSingleStep(test); err != nil {
+					t.Errorf("opcode %s, test %q: %v", opcode, test.Name, err)
+					return
+				}
+			}
+		})
+	}
+}
+
+// TestSingleStep_divergence checks divergences are reported by cycle & field.
+func TestSingleStep_divergence(t *testing.T) {
+	const vector = `{
+		"name": "06 0000",
+		"initial": {"a": 0, "b": 0, "c": 0, "d": 0, "e": 0, "f": 0, "h": 0, "l": 0,
+			"pc": 513, "sp": 0, "ime": 0, "ram": [[512, 6], [513, 66], [514, 0]]},
+		"final": {"a": 0, "b": 66, "c": 0, "d": 0, "e": 0, "f": 0, "h": 0, "l": 0,
+			"pc": 515, "sp": 0, "ime": 0, "ram": [[512, 6], [513, 66], [514, 0]]},
+		"cycles": [[513, 66, "r-m"], [514, 0, "r-m"]]
+	}`
+	var test sstTest
+	require.NoError(t, json.Unmarshal([]byte(vector), &test))
+	assert.NoError(t, runSingleStep(test))
+
+	tests := map[string]func(*sstTest){
+		"cycle 1: address: want $0203, got $0202":  func(test *sstTest) { test.Cycles[1].Addr = 0x0203 },
+		"cycle 0: data: want $43, got $42":         func(test *sstTest) { *test.Cycles[0].Data = 0x43 },
+		"cycle 1: pins: want -wm, got r-m":         func(test *sstTest) { test.Cycles[1].Read, test.Cycles[1].Write = false, true },
+		"instruction still running after 1 cycles": func(test *sstTest) { test.Cycles = test.Cycles[:1] },
+		"final B: want $41, got $42":               func(test *sstTest) { test.Final.B = 0x41 },
+		"final ($0202): want $07, got $00":         func(test *sstTest) { test.Final.RAM = [][2]uint16{{0x0202, 7}} },
+	}
+	for msg, modify := range tests {
+		var test sstTest
+		require.NoError(t, json.Unmarshal([]byte(vector), &test))
+		modify(&test)
+		assert.EqualError(t, runSingleStep(test), msg)
+	}
+}
@@ -3,6 +3,8 @@ package cpu
 import (
 	"fmt"
 	"log/slog"
+
+	"github.com/wmarshpersonal/gogeebee/model"
 )
 
 // State represents the internal CPU state.
@@ -13,16 +15,36 @@ type State struct {
 	Z, W, ALUResult uint8 // internal registers
 	S               int   // current cycle-step in instruction
 	IME             bool  // interrupts enabled
+	IMEPending      bool  // IME is set once the next instruction completes (EI)
 	IE, IF          uint8 // interrupt enable, interrupt flag
 	Interrupting    bool  // interrupt logic is active
 	Halted          bool  // cpu is in halt state
+	Stopped         bool  // cpu is in stop mode
+	Locked          bool  // cpu is locked up by an illegal opcode, until reset
 	CB              bool  // CB mode?
 
+	SpeedSwitch bool // CGB speed switch armed (KEY1 bit 0)
+	DoubleSpeed bool // CGB double speed mode (KEY1 bit 7)
+
+	Signals Signal // one-shot outputs raised during the last cycle
+
 	// register file
 	B, C, D, E, H, L, A, F uint8
 	PC, SP                 uint16
 }
 
+// Idle reports whether the CPU is not executing cycles (halted, stopped or locked).
+// StartCycle & FinishCycle must not be called while the CPU is idle.
+func (s State) Idle() bool {
+	return s.Halted || s.Stopped || s.Locked
+}
+
+// Pending returns the interrupts that are both requested & enabled. Only the
+// low 5 bits of IE & IF are interrupts, so the unused bits are masked off.
+func (s State) Pending() uint8 {
+	return s.IF & s.IE & 0x1F
+}
+
 func (s State) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.String("AF", fmt.Sprintf("$%02x%02x", s.A, s.F)),
@@ -32,12 +54,19 @@ func (s State) LogValue() slog.Value {
 		slog.String("SP", fmt.Sprintf("$%04x", s.SP)),
 		slog.String("PC", fmt.Sprintf("$%04x", s.PC)),
 		slog.Bool("IME", s.IME),
+		slog.Bool("IMEPending", s.IMEPending),
 		slog.String("IR", fmt.Sprintf("$%02x", s.IR)),
 		slog.Any("S", s.S),
 	)
 }
 
-// NewResetState returns the state of the CPU after the GB boot rom has run.
+// NewPowerOnState returns the state of the CPU at power on, about to run the
+// boot rom from $0000. Its first cycle is a NOP, which fetches the first opcode.
+func NewPowerOnState() *State {
+	return &State{}
+}
+
+// NewResetState returns the state of the CPU after the DMG boot rom has run.
 // AF  = 01B0
 // BC  = 0013
 // DE  = 00D8
@@ -49,22 +78,44 @@ func (s State) LogValue() slog.Value {
 // IR	 = E0
 // S   = 02
 func NewResetState() *State {
-	return &State{
-		B:   0x00,
-		C:   0x13,
-		D:   0x00,
-		E:   0xD8,
-		H:   0x01,
-		L:   0x4D,
-		A:   0x01,
-		F:   0xB0,
+	return NewResetStateFor(model.DMG)
+}
+
+// NewResetStateFor returns the state of the CPU after the model's boot rom has
+// run. Games tell the models apart by the registers: A is $11 on the CGB & AGB
+// (where the AGB also sets bit 0 of B), and $FF on the MGB & SGB2.
+//
+// The DMG & MGB set the H & C flags unless the header checksum is 0, as is the
+// case for almost every cartridge. The CGB & AGB registers are those of CGB
+// mode.
+func NewResetStateFor(m model.Model) *State {
+	s := &State{
 		PC:  0x0100,
 		IME: false,
 		IF:  0xE1,
 		SP:  0xFFFE,
-		IR:  0xE0,
+		IR:  0xE0, // ldh ($50), a, unmapping the boot rom
 		S:   0x02,
 	}
+	switch m {
+	case model.DMG0:
+		s.A, s.F, s.B, s.C, s.D, s.E, s.H, s.L = 0x01, 0x00, 0xFF, 0x13, 0x00, 0xC1, 0x84, 0x03
+	case model.DMG:
+		s.A, s.F, s.B, s.C, s.D, s.E, s.H, s.L = 0x01, 0xB0, 0x00, 0x13, 0x00, 0xD8, 0x01, 0x4D
+	case model.MGB:
+		s.A, s.F, s.B, s.C, s.D, s.E, s.H, s.L = 0xFF, 0xB0, 0x00, 0x13, 0x00, 0xD8, 0x01, 0x4D
+	case model.SGB:
+		s.A, s.F, s.B, s.C, s.D, s.E, s.H, s.L = 0x01, 0x00, 0x00, 0x14, 0x00, 0x00, 0xC0, 0x60
+	case model.SGB2:
+		s.A, s.F, s.B, s.C, s.D, s.E, s.H, s.L = 0xFF, 0x00, 0x00, 0x14, 0x00, 0x00, 0xC0, 0x60
+	case model.CGB:
+		s.A, s.F, s.B, s.C, s.D, s.E, s.H, s.L = 0x11, 0x80, 0x00, 0x00, 0xFF, 0x56, 0x00, 0x0D
+	case model.AGB:
+		s.A, s.F, s.B, s.C, s.D, s.E, s.H, s.L = 0x11, 0x00, 0x01, 0x00, 0xFF, 0x56, 0x00, 0x0D
+	default:
+		panic(fmt.Sprintf("unknown model %v", m))
+	}
+	return s
 }
 
 const (
@@ -105,7 +156,7 @@ func (s State) R8(r R8) uint8 {
 	case A:
 		return s.A
 	default:
-		panic("invalid register")
+		panic(InvalidRegisterError{Register: uint8(r)})
 	}
 }
 
@@ -127,7 +178,7 @@ func (s *State) R8Set(r R8, v uint8) {
 	case A:
 		s.A = v
 	default:
-		panic("invalid register")
+		panic(InvalidRegisterError{Register: uint8(r)})
 	}
 }
 
@@ -161,7 +212,7 @@ func (s State) R16(rr R16) uint16 {
 	case WZ:
 		return mk16(s.W, s.Z)
 	default:
-		panic("invalid register")
+		panic(InvalidRegisterError{Register: uint8(rr)})
 	}
 }
 
@@ -175,7 +226,7 @@ func (s *State) R16Set(rr R16, v uint16) {
 	case HL:
 		s.H, s.L = hi(v), lo(v)
 	case AF:
-		s.A, s.F = hi(v), lo(v)
+		s.A, s.F = hi(v), lo(v)&0xF0 // the low bits of F are always 0
 	case SP:
 		s.SP = v
 	case PC:
@@ -183,6 +234,6 @@ func (s *State) R16Set(rr R16, v uint16) {
 	case WZ:
 		s.W, s.Z = hi(v), lo(v)
 	default:
-		panic("invalid register")
+		panic(InvalidRegisterError{Register: uint8(rr)})
 	}
 }
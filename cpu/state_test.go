@@ -0,0 +1,21 @@
+package cpu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wmarshpersonal/gogeebee/model"
+)
+
+func TestNewResetStateFor(t *testing.T) {
+	assert.Equal(t, NewResetStateFor(model.DMG), NewResetState())
+
+	for _, m := range model.Models {
+		s := NewResetStateFor(m)
+		assert.Equalf(t, m.Color(), s.A == 0x11, "%v: A identifies CGB hardware", m)
+		assert.Equalf(t, m == model.AGB, m.Color() && s.B&1 == 1, "%v: B bit 0 identifies the AGB", m)
+		assert.Equalf(t, m == model.MGB || m == model.SGB2, s.A == 0xFF, "%v: A identifies the MGB & SGB2", m)
+		assert.Zerof(t, s.F&0x0F, "%v: low bits of F", m)
+		assert.Equalf(t, uint16(0xFFFE), s.SP, "%v: SP", m)
+	}
+}
@@ -0,0 +1,133 @@
+package cpu
+
+// Bus is the memory bus the CPU reads & writes during its cycles.
+// Every M-cycle the CPU calls exactly one of Read, Write or Tick (for cycles
+// without a bus access), so implementations can clock the rest of the
+// hardware from these calls.
+type Bus interface {
+	Read(addr uint16) uint8
+	Write(addr uint16, v uint8)
+	Tick()
+}
+
+// InterruptSource is implemented by buses with hardware that requests interrupts.
+type InterruptSource interface {
+	// Interrupts returns the interrupts requested since it was last called, as IF bits.
+	Interrupts() uint8
+}
+
+// FuncBus adapts a pair of memory access functions to a Bus.
+// Cycles without a bus access are ignored.
+type FuncBus struct {
+	ReadMem  ReadMemFunc
+	WriteMem WriteMemFunc
+}
+
+func (b FuncBus) Read(addr uint16) uint8     { return b.ReadMem(addr) }
+func (b FuncBus) Write(addr uint16, v uint8) { b.WriteMem(addr, v) }
+func (b FuncBus) Tick()                      {}
+
+// Access is a memory access made by the CPU during a cycle.
+type Access struct {
+	Addr  uint16
+	Data  uint8
+	Write bool
+}
+
+// cycleInfo describes a cycle run by step.
+type cycleInfo struct {
+	Cycle    Cycle  // cycle as executed, including any fetch
+	Idle     bool   // the CPU was idle and executed nothing
+	Addr     uint16 // address selected onto the bus
+	Access   Access // memory access of the cycle...
+	Accessed bool   // ...if there was one
+}
+
+// Step runs a single M-cycle of the CPU, performing its memory access on the bus.
+// The IE ($FFFF) & IF ($FF0F) registers are part of the CPU state, so accesses
+// to them are handled by Step and reach the bus as a Tick.
+// If the bus is an InterruptSource, its requests are added to IF at the end of the cycle.
+//
+// Instead of panicking, Step returns an error (InvalidOpcodeError, InvalidRegisterError,
+// InvalidOpError or InvalidStateError) locating the fault, along with the state as it
+// was before the step.
+func Step(s State, bus Bus) (State, error) {
+	return defaultInstructionSet.Step(s, bus)
+}
+
+// Step is the package-level Step, running the opcodes of the instruction set.
+func (is *InstructionSet) Step(s State, bus Bus) (State, error) {
+	s, _, err := is.step(s, bus)
+	return s, err
+}
+
+func (is *InstructionSet) step(s State, bus Bus) (next State, info cycleInfo, err error) {
+	fault := Fault{PC: s.PC, IR: s.IR, CB: s.CB, S: s.S}
+	defer func() {
+		if r := recover(); r != nil {
+			next, info, err = s, cycleInfo{}, faultError(r, fault)
+		}
+	}()
+
+	next, cycle := is.NextCycle(s)
+	fault.S = next.S
+	if next.Idle() {
+		bus.Tick()
+		return interrupts(next, bus), cycleInfo{Idle: true}, nil
+	}
+
+	next, cycle = StartCycle(next, cycle)
+	addr := cycle.Addr.Do(next)
+	info.Cycle, info.Addr = cycle, addr
+
+	var data uint8
+	if cycle.Data.RD() {
+		data = busRead(&next, bus, addr)
+		info.Access, info.Accessed = Access{Addr: addr, Data: data}, true
+	} else if wr, v := cycle.Data.WR(next, next.IR); wr {
+		busWrite(&next, bus, addr, v)
+		info.Access, info.Accessed = Access{Addr: addr, Data: v, Write: true}, true
+	} else {
+		bus.Tick()
+	}
+
+	return interrupts(FinishCycle(next, cycle, data), bus), info, nil
+}
+
+// busRead reads addr from the bus, or from the IE & IF registers held in the
+// state, clocking the bus with a Tick instead.
+func busRead(s *State, bus Bus, addr uint16) uint8 {
+	switch addr {
+	case 0xFF0F:
+		bus.Tick()
+		return s.IF | 0xE0
+	case 0xFFFF:
+		bus.Tick()
+		return s.IE
+	default:
+		return bus.Read(addr)
+	}
+}
+
+// busWrite writes v to addr on the bus, or to the IE & IF registers held in
+// the state, clocking the bus with a Tick instead.
+func busWrite(s *State, bus Bus, addr uint16, v uint8) {
+	switch addr {
+	case 0xFF0F:
+		s.IF = v & 0x1F
+		bus.Tick()
+	case 0xFFFF:
+		s.IE = v
+		bus.Tick()
+	default:
+		bus.Write(addr, v)
+	}
+}
+
+// interrupts adds the bus's interrupt requests to IF.
+func interrupts(s State, bus Bus) State {
+	if src, ok := bus.(InterruptSource); ok {
+		s.IF |= src.Interrupts() & 0x1F
+	}
+	return s
+}
@@ -0,0 +1,89 @@
+package cpu
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type flatBus [0x10000]uint8
+
+func (b *flatBus) Read(addr uint16) uint8     { return b[addr] }
+func (b *flatBus) Write(addr uint16, v uint8) { b[addr] = v }
+func (b *flatBus) Tick()                      {}
+
+func TestStep(t *testing.T) {
+	t.Run("runs a program", func(t *testing.T) {
+		assert := assert.New(t)
+		var bus flatBus
+		copy(bus[0x100:], []uint8{
+			0x3E, 0x42, // ld a, $42
+			0xEA, 0x00, 0xC0, // ld ($C000), a
+			0xE0, 0xFF, // ldh ($FF), a
+		})
+		s := State{PC: 0x0101, IR: bus[0x100]}
+
+		for range 2 + 4 + 3 {
+			var err error
+			s, err = Step(s, &bus)
+			if !assert.NoError(err) {
+				return
+			}
+		}
+		assert.EqualValues(0x42, s.A)
+		assert.EqualValues(0x42, bus[0xC000])
+		assert.EqualValues(0x42, s.IE, "IE is held by the CPU")
+		assert.Zero(bus[0xFFFF])
+	})
+
+	t.Run("invalid opcode", func(t *testing.T) {
+		defer func(op Opcode) { operations[0xD3] = op }(operations[0xD3])
+		operations[0xD3] = nil
+
+		var bus flatBus
+		s := State{PC: 0x1235, IR: 0xD3}
+		next, err := Step(s, &bus)
+
+		var opcodeErr InvalidOpcodeError
+		if assert.True(t, errors.As(err, &opcodeErr)) {
+			assert.Equal(t, Fault{PC: 0x1235, IR: 0xD3}, opcodeErr.Fault)
+		}
+		assert.Equal(t, s, next)
+	})
+
+	t.Run("invalid register", func(t *testing.T) {
+		defer func(op Opcode) { operations[0xF4] = op }(operations[0xF4])
+		operations[0xF4] = Opcode{
+			{Addr: AddrPC, Data: ReadZ, IDU: Inc},
+			{Addr: AddrPC, ALU: LD_r_Z, Fetch: true}, // r = 0b110
+		}
+
+		var bus flatBus
+		s := State{PC: 0x0100, IR: 0xF4}
+		s, err := Step(s, &bus)
+		assert.NoError(t, err)
+		_, err = Step(s, &bus)
+
+		var registerErr InvalidRegisterError
+		if assert.True(t, errors.As(err, &registerErr)) {
+			assert.Equal(t, Fault{PC: 0x0101, IR: 0xF4, S: 1}, registerErr.Fault)
+			assert.EqualValues(t, 6, registerErr.Register)
+		}
+	})
+
+	t.Run("invalid op", func(t *testing.T) {
+		defer func(op Opcode) { operations[0xFD] = op }(operations[0xFD])
+		operations[0xFD] = Opcode{
+			{Addr: AddrHI_plus_C, IDU: Inc},
+		}
+
+		var bus flatBus
+		_, err := Step(State{PC: 0x0100, IR: 0xFD}, &bus)
+
+		var opErr InvalidOpError
+		if assert.True(t, errors.As(err, &opErr)) {
+			assert.Equal(t, AddrHI_plus_C, opErr.Op)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package cpu
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// TraceFormat is the line format written by a Tracer.
+type TraceFormat int
+
+const (
+	// TraceDoctor is the Gameboy Doctor format:
+	//  A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0100 PCMEM:00,C3,13,02
+	TraceDoctor TraceFormat = iota
+	// TraceSlog is the text slog form of State.LogValue.
+	TraceSlog
+)
+
+// Peeker is implemented by buses that can read memory without side effects.
+// A Core only writes the PCMEM of Gameboy Doctor traces for such buses.
+type Peeker interface {
+	Peek(addr uint16) uint8
+}
+
+// Tracer writes an execution trace, one line per instruction executed, for
+// diffing against the logs of other emulators.
+type Tracer struct {
+	Format TraceFormat
+
+	// From & To limit the trace to instructions at addresses in [From, To].
+	// A To of 0 leaves the range without an upper bound.
+	From, To uint16
+
+	// MaxLines stops the trace after that many lines, if non-zero.
+	MaxLines int
+
+	w      io.Writer
+	logger *slog.Logger
+	lines  int
+}
+
+// NewTracer returns a tracer writing every instruction to w in the Gameboy Doctor format.
+func NewTracer(w io.Writer) *Tracer {
+	return &Tracer{w: w}
+}
+
+// Lines returns the number of lines written.
+func (t *Tracer) Lines() int {
+	return t.lines
+}
+
+// Trace writes a line for the instruction at pc, the address its opcode was
+// fetched from, if the next cycle from the state starts executing it: the opcode
+// has been fetched (S is 0) and the CPU isn't idle or about to dispatch an
+// interrupt. It must be called before every cycle. pc is usually PC-1, but not
+// after the HALT bug, which fetches the opcode without advancing PC.
+// PCMEM is read with peek, and left out if peek is nil.
+func (t *Tracer) Trace(s State, pc uint16, peek ReadMemFunc) error {
+	if t.MaxLines != 0 && t.lines >= t.MaxLines {
+		return nil
+	}
+	next := s
+	beginCycle(&next)
+	if next.Idle() || next.Interrupting || next.S != 0 {
+		return nil
+	}
+	if pc < t.From || t.To != 0 && pc > t.To {
+		return nil
+	}
+	t.lines++
+
+	if t.Format == TraceSlog {
+		if t.logger == nil {
+			t.logger = slog.New(slog.NewTextHandler(t.w, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey && len(groups) == 0 {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+		}
+		t.logger.LogAttrs(context.Background(), slog.LevelInfo, "trace", slog.Any("state", s))
+		return nil
+	}
+
+	line := fmt.Sprintf("A:%02X F:%02X B:%02X C:%02X D:%02X E:%02X H:%02X L:%02X SP:%04X PC:%04X",
+		s.A, s.F, s.B, s.C, s.D, s.E, s.H, s.L, s.SP, pc)
+	if peek != nil {
+		line += fmt.Sprintf(" PCMEM:%02X,%02X,%02X,%02X", peek(pc), peek(pc+1), peek(pc+2), peek(pc+3))
+	}
+	_, err := fmt.Fprintln(t.w, line)
+	return err
+}
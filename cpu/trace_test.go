@@ -0,0 +1,127 @@
+package cpu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// peekBus is a flat bus that can be peeked.
+type peekBus struct {
+	flatBus
+}
+
+func (b *peekBus) Peek(addr uint16) uint8 { return b.flatBus[addr] }
+
+// newTraceCore returns a core running program from $0100, traced into sb.
+func newTraceCore(sb *strings.Builder, program ...uint8) *Core {
+	bus := &peekBus{}
+	copy(bus.flatBus[0x100:], program)
+	c := NewCore(bus)
+	c.IR, c.S, c.PC = bus.flatBus[0x100], 0, 0x0101
+	c.IF = 0
+	c.Tracer = NewTracer(sb)
+	return c
+}
+
+func TestTracer(t *testing.T) {
+	t.Run("gameboy doctor format", func(t *testing.T) {
+		var sb strings.Builder
+		c := newTraceCore(&sb,
+			0x3E, 0x42, // ld a, $42
+			0xCB, 0x37, // swap a
+			0x18, 0xFE, // jr -2
+		)
+		_, err := c.RunCycles(2 + 2 + 3 + 1)
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Join([]string{
+			"A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0100 PCMEM:3E,42,CB,37",
+			"A:42 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0102 PCMEM:CB,37,18,FE",
+			"A:24 F:00 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0104 PCMEM:18,FE,00,00",
+			"A:24 F:00 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0104 PCMEM:18,FE,00,00",
+			"",
+		}, "\n"), sb.String())
+	})
+
+	t.Run("PCMEM needs a Peeker", func(t *testing.T) {
+		var sb strings.Builder
+		assert.NoError(t, NewTracer(&sb).Trace(State{PC: 0x0101}, 0x0100, nil))
+		assert.Equal(t, "A:00 F:00 B:00 C:00 D:00 E:00 H:00 L:00 SP:0000 PC:0100\n", sb.String())
+	})
+
+	t.Run("filters & line cap", func(t *testing.T) {
+		var sb strings.Builder
+		c := newTraceCore(&sb, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00)
+		c.Tracer.From, c.Tracer.To, c.Tracer.MaxLines = 0x0101, 0x0104, 2
+		_, err := c.RunCycles(6)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, c.Tracer.Lines())
+		lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+		assert.Len(t, lines, 2)
+		assert.Contains(t, lines[0], "PC:0101 ")
+		assert.Contains(t, lines[1], "PC:0102 ")
+	})
+
+	t.Run("zero To is unbounded", func(t *testing.T) {
+		var sb strings.Builder
+		c := newTraceCore(&sb, 0x00, 0x00, 0x00)
+		c.Tracer.From = 0x0101
+		_, err := c.RunCycles(3)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, c.Tracer.Lines())
+	})
+
+	t.Run("interrupts & halt", func(t *testing.T) {
+		var sb strings.Builder
+		c := newTraceCore(&sb,
+			0x76, // halt
+			0x3C, // inc a
+		)
+		c.Bus.(*peekBus).flatBus[0x40] = 0x00 // nop
+		c.IE = IntVBlank
+
+		_, err := c.RunCycles(10)
+		assert.NoError(t, err)
+		assert.True(t, c.Halted)
+
+		// the preempted instruction isn't traced, only the handler
+		c.IME, c.IF = true, IntVBlank
+		_, err = c.RunCycles(5 + 1)
+		assert.NoError(t, err)
+
+		var pcs []string
+		for _, line := range strings.Split(strings.TrimSpace(sb.String()), "\n") {
+			pcs = append(pcs, strings.Fields(line)[9])
+		}
+		assert.Equal(t, []string{"PC:0100", "PC:0040"}, pcs)
+	})
+
+	t.Run("halt bug", func(t *testing.T) {
+		var sb strings.Builder
+		c := newTraceCore(&sb,
+			0x76, // halt
+			0x3C, // inc a
+		)
+		c.IE, c.IF = IntVBlank, IntVBlank
+
+		// inc a is fetched twice from $0101, as PC fails to advance past it
+		_, err := c.RunCycles(1 + 1 + 1)
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Join([]string{
+			"A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0100 PCMEM:76,3C,00,00",
+			"A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0101 PCMEM:3C,00,00,00",
+			"A:02 F:10 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0101 PCMEM:3C,00,00,00",
+			"",
+		}, "\n"), sb.String())
+	})
+
+	t.Run("slog format", func(t *testing.T) {
+		var sb strings.Builder
+		c := newTraceCore(&sb, 0x00)
+		c.Tracer.Format = TraceSlog
+		assert.NoError(t, c.Step())
+		assert.Equal(t, `level=INFO msg=trace state.AF=$01b0 state.BC=$0013 state.DE=$00d8 state.HL=$014d `+
+			`state.SP=$fffe state.PC=$0101 state.IME=false state.IMEPending=false state.IR=$00 state.S=0`+"\n", sb.String())
+	})
+}
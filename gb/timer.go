@@ -1,5 +1,7 @@
 package gb
 
+import "github.com/wmarshpersonal/gogeebee/model"
+
 // Timer encapsulates the functionality of the Game Boy's timer and divider systems
 type Timer struct {
 	counter uint16 // System counter 14-bits
@@ -9,15 +11,37 @@ type Timer struct {
 
 	busData      uint8
 	writeSignals TimerReg
-	delay        uint8 // TIMA load signal delay line
+	delay        uint8  // TIMA load signal delay line
+	falling      uint16 // System counter bits that fell on the last step
 
 	IR bool // Interrupt request
 }
 
 // DMGTimer returns a timer with initial values set for the DMG model Game Boy
 func DMGTimer() Timer {
+	return TimerFor(model.DMG)
+}
+
+// TimerFor returns a timer with initial values set for the model, after its boot rom has run.
+// The counter's phase depends on how long the boot rom ran for. The SGB boot rom waits
+// on the SNES, so its phase is unknown, and the CGB's depends on the cartridge header,
+// so it is that of a typical CGB game.
+func TimerFor(m model.Model) Timer {
+	var counter uint16
+	switch m {
+	case model.DMG0:
+		counter = 0x18 << 6
+	case model.DMG, model.MGB:
+		counter = 0xABCC >> 2
+	case model.SGB, model.SGB2:
+		counter = 0
+	case model.CGB, model.AGB:
+		counter = 0x1EA0 >> 2
+	default:
+		panic("unknown model")
+	}
 	return Timer{
-		counter: 0xAB << 6,
+		counter: counter,
 		tima:    0x00,
 		tma:     0x00,
 		tac:     0xF8,
@@ -48,6 +72,33 @@ func (t Timer) Read(reg TimerReg) uint8 {
 	}
 }
 
+// ReloadPending reports whether TIMA overflowed on the last step, and so is
+// reloaded from TMA (raising IR) on the next.
+func (t Timer) ReloadPending() bool {
+	return t.delay&1 == 1
+}
+
+// System counter bits that clock other blocks, as taps for CounterBit & Fell.
+// The counter counts M-cycles, so DIV is bits 6-13.
+const (
+	SerialClockBit      = 6  // Serial internal clock, 8192Hz (16384Hz in double speed)
+	SerialFastClockBit  = 1  // CGB fast serial internal clock, 262144Hz
+	FrameSequencerBit   = 10 // APU frame sequencer, DIV bit 4
+	FrameSequencerDSBit = 11 // APU frame sequencer in double speed, DIV bit 5
+)
+
+// CounterBit returns bit n of the 14-bit system counter.
+func (t Timer) CounterBit(n int) bool {
+	return t.counter>>n&1 == 1
+}
+
+// Fell reports whether bit n of the system counter fell on the last step, which
+// clocks the blocks tapping it. Writes to DIV reset the counter, so they can
+// clock them early.
+func (t Timer) Fell(n int) bool {
+	return t.falling>>n&1 == 1
+}
+
 // Write writes to the selected register.
 // The updated timer state is returned.
 func (t Timer) Write(reg TimerReg, v uint8) Timer {
@@ -66,8 +117,9 @@ func (t Timer) Step() Timer {
 	if t.writeSignals&DIV == DIV {
 		t.counter = 0
 	} else { // ...or increment DIV
-		t.counter = t.counter + 1&0b11111111111111 // system counter is 14-bits
+		t.counter = (t.counter + 1) & 0b11111111111111 // system counter is 14-bits
 	}
+	t.falling = prev.counter &^ t.counter
 
 	// apply TMA write
 	if t.writeSignals&TMA == TMA {
@@ -79,16 +131,14 @@ func (t Timer) Step() Timer {
 		t.tac = t.busData
 	}
 
-	// "enabled" flag is 3rd bit of TAC
-	if t.tac&0b100 == 0b100 {
-		// DIV edge fell?
-		if counterSignal(uint8(prev.counter&0xFF), prev.tac) && !counterSignal(uint8(t.counter&0xFF), t.tac) {
-			// TIMA tick
-			t.tima++
-		}
+	// TIMA ticks on the falling edge of the counter bit selected by TAC ANDed
+	// with TAC's enable bit, so writes to DIV & TAC can tick it too
+	if timerSignal(prev.counter, prev.tac) && !timerSignal(t.counter, t.tac) {
+		t.tima++
 	}
 
-	// apply TIMA write
+	// apply TIMA write. A write in the cycle TIMA overflows cancels the reload,
+	// and one in the cycle it's reloaded is lost to TMA.
 	if t.writeSignals&TIMA == TIMA {
 		t.tima = t.busData
 	}
@@ -102,7 +152,8 @@ func (t Timer) Step() Timer {
 	}
 	t.delay >>= 1
 
-	// set TIMA to TMA if delay shifts out a value
+	// set TIMA to TMA if delay shifts out a value. TMA is written before this,
+	// so a write to TMA in the cycle TIMA is reloaded is loaded too.
 	t.IR = false
 	if prev.delay&1 == 1 {
 		t.IR = true
@@ -114,8 +165,13 @@ func (t Timer) Step() Timer {
 	return t
 }
 
-func counterSignal(counter uint8, tac uint8) bool {
-	var mask uint8
+// timerSignal is the input of TIMA's falling edge detector: the counter bit
+// selected by TAC, ANDed with TAC's enable bit.
+func timerSignal(counter uint16, tac uint8) bool {
+	if tac&0b100 == 0 {
+		return false
+	}
+	var mask uint16
 	switch tac & 0b11 {
 	case 0:
 		mask = 0b10000000
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/wmarshpersonal/gogeebee/model"
 )
 
 func TestTimer_Counter(t *testing.T) {
@@ -35,6 +36,20 @@ func TestTimer_Counter(t *testing.T) {
 	})
 }
 
+func TestTimerFor(t *testing.T) {
+	assert.Equal(t, TimerFor(model.DMG), DMGTimer())
+
+	for m, div := range map[model.Model]uint8{
+		model.DMG0: 0x18,
+		model.DMG:  0xAB,
+		model.MGB:  0xAB,
+		model.CGB:  0x1E,
+		model.AGB:  0x1E,
+	} {
+		assert.Equalf(t, div, TimerFor(m).Read(DIV), "%v", m)
+	}
+}
+
 func TestTimer_TIMA(t *testing.T) {
 	t.Run("TIMA increments every 256 cycles when TAC is 0b100", func(t *testing.T) {
 		tickTest(t,
@@ -117,11 +132,14 @@ func TestTimer_Modulus(t *testing.T) {
 		timer = timer.Step()
 		assert.Exactly(uint16(0x30), timer.counter)
 		assert.Exactly(uint8(0x00), timer.tima)
+		assert.True(timer.ReloadPending())
 
 		// step 3 - set to TMA
 		timer = timer.Step()
 		assert.Exactly(uint16(0x31), timer.counter)
 		assert.Exactly(uint8(0x23), timer.tima)
+		assert.False(timer.ReloadPending())
+		assert.True(timer.IR)
 	})
 }
 
@@ -191,3 +209,120 @@ func TestTimer_Weird(t *testing.T) {
 		assert.Exactly(uint8(0x00), timer.tima)
 	})
 }
+
+// TestTimer_Mooneye reproduces the scenarios of the mooneye-test-suite's
+// acceptance/timer roms, cycle by cycle.
+func TestTimer_Mooneye(t *testing.T) {
+	type write struct {
+		cycle int // the cycle written in, counting from 0
+		reg   TimerReg
+		v     uint8
+	}
+	tests := []struct {
+		name           string
+		counter        uint16
+		tac, tima, tma uint8
+		writes         []write
+		cycles         int // cycles stepped before reading
+		read           TimerReg
+		want           uint8
+		wantIR         bool
+	}{
+		{name: "div_write: DIV is reset", counter: 0x1234, writes: []write{{0, DIV, 0xFF}}, cycles: 64, read: DIV, want: 0},
+		{name: "div_write: DIV increments 64 cycles after reset", counter: 0x1234, writes: []write{{0, DIV, 0xFF}}, cycles: 65, read: DIV, want: 1},
+
+		{name: "tim00: before tick", tac: 0b100, writes: []write{{0, DIV, 0}}, cycles: 256, read: TIMA, want: 0},
+		{name: "tim00: tick", tac: 0b100, writes: []write{{0, DIV, 0}}, cycles: 257, read: TIMA, want: 1},
+		{name: "tim01: before tick", tac: 0b101, writes: []write{{0, DIV, 0}}, cycles: 4, read: TIMA, want: 0},
+		{name: "tim01: tick", tac: 0b101, writes: []write{{0, DIV, 0}}, cycles: 5, read: TIMA, want: 1},
+		{name: "tim10: before tick", tac: 0b110, writes: []write{{0, DIV, 0}}, cycles: 16, read: TIMA, want: 0},
+		{name: "tim10: tick", tac: 0b110, writes: []write{{0, DIV, 0}}, cycles: 17, read: TIMA, want: 1},
+		{name: "tim11: before tick", tac: 0b111, writes: []write{{0, DIV, 0}}, cycles: 64, read: TIMA, want: 0},
+		{name: "tim11: tick", tac: 0b111, writes: []write{{0, DIV, 0}}, cycles: 65, read: TIMA, want: 1},
+
+		{name: "tim00_div_trigger: bit set", counter: 128, tac: 0b100, writes: []write{{0, DIV, 0}}, cycles: 1, read: TIMA, want: 1},
+		{name: "tim00_div_trigger: bit clear", counter: 127, tac: 0b100, writes: []write{{0, DIV, 0}}, cycles: 1, read: TIMA, want: 0},
+		{name: "tim01_div_trigger: bit set", counter: 2, tac: 0b101, writes: []write{{0, DIV, 0}}, cycles: 1, read: TIMA, want: 1},
+		{name: "tim01_div_trigger: bit clear", counter: 1, tac: 0b101, writes: []write{{0, DIV, 0}}, cycles: 1, read: TIMA, want: 0},
+		{name: "tim10_div_trigger: bit set", counter: 8, tac: 0b110, writes: []write{{0, DIV, 0}}, cycles: 1, read: TIMA, want: 1},
+		{name: "tim10_div_trigger: bit clear", counter: 7, tac: 0b110, writes: []write{{0, DIV, 0}}, cycles: 1, read: TIMA, want: 0},
+		{name: "tim11_div_trigger: bit set", counter: 32, tac: 0b111, writes: []write{{0, DIV, 0}}, cycles: 1, read: TIMA, want: 1},
+		{name: "tim11_div_trigger: bit clear", counter: 31, tac: 0b111, writes: []write{{0, DIV, 0}}, cycles: 1, read: TIMA, want: 0},
+		{name: "div_trigger: not while disabled", counter: 128, tac: 0b000, writes: []write{{0, DIV, 0}}, cycles: 1, read: TIMA, want: 0},
+
+		{name: "rapid_toggle: disabling with the bit set ticks", counter: 0x80, tac: 0b100,
+			writes: []write{{0, TAC, 0b000}, {1, TAC, 0b100}, {2, TAC, 0b000}, {3, TAC, 0b100}}, cycles: 4, read: TIMA, want: 2},
+		{name: "rapid_toggle: enabling with the bit set doesn't tick", counter: 3, tac: 0b001,
+			writes: []write{{0, TAC, 0b101}}, cycles: 1, read: TIMA, want: 0},
+		{name: "rapid_toggle: changing to a clear bit ticks", counter: 0x80, tac: 0b100,
+			writes: []write{{0, TAC, 0b101}}, cycles: 1, read: TIMA, want: 1},
+		{name: "rapid_toggle: changing to a set bit doesn't tick", counter: 0x02, tac: 0b100,
+			writes: []write{{0, TAC, 0b101}}, cycles: 1, read: TIMA, want: 0},
+
+		// TIMA overflows in cycle 0 (A) & is reloaded in cycle 1 (B)
+		{name: "tima_reload: TIMA reads 0 after overflow", counter: 3, tac: 0b101, tima: 0xFF, tma: 0x23, cycles: 1, read: TIMA, want: 0x00},
+		{name: "tima_reload: TIMA is reloaded a cycle later", counter: 3, tac: 0b101, tima: 0xFF, tma: 0x23, cycles: 2, read: TIMA, want: 0x23, wantIR: true},
+		{name: "tima_write_reloading: write in A cancels reload", counter: 3, tac: 0b101, tima: 0xFF, tma: 0x23,
+			writes: []write{{0, TIMA, 0x80}}, cycles: 2, read: TIMA, want: 0x80},
+		{name: "tima_write_reloading: write in B is ignored", counter: 3, tac: 0b101, tima: 0xFF, tma: 0x23,
+			writes: []write{{1, TIMA, 0x80}}, cycles: 2, read: TIMA, want: 0x23, wantIR: true},
+		{name: "tima_write_reloading: write after B", counter: 3, tac: 0b101, tima: 0xFF, tma: 0x23,
+			writes: []write{{2, TIMA, 0x80}}, cycles: 3, read: TIMA, want: 0x80},
+		{name: "tma_write_reloading: write in A is reloaded", counter: 3, tac: 0b101, tima: 0xFF, tma: 0x23,
+			writes: []write{{0, TMA, 0x42}}, cycles: 2, read: TIMA, want: 0x42, wantIR: true},
+		{name: "tma_write_reloading: write in B is reloaded", counter: 3, tac: 0b101, tima: 0xFF, tma: 0x23,
+			writes: []write{{1, TMA, 0x42}}, cycles: 2, read: TIMA, want: 0x42, wantIR: true},
+		{name: "tma_write_reloading: write after B", counter: 3, tac: 0b101, tima: 0xFF, tma: 0x23,
+			writes: []write{{2, TMA, 0x42}}, cycles: 3, read: TIMA, want: 0x23},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timer := Timer{counter: tt.counter, tac: tt.tac, tima: tt.tima, tma: tt.tma}
+			for cycle := range tt.cycles {
+				for _, w := range tt.writes {
+					if w.cycle == cycle {
+						timer = timer.Write(w.reg, w.v)
+					}
+				}
+				timer = timer.Step()
+			}
+			assert.Equal(t, tt.want, timer.Read(tt.read))
+			assert.Equal(t, tt.wantIR, timer.IR, "IR")
+		})
+	}
+}
+
+func TestTimer_Taps(t *testing.T) {
+	t.Run("frame sequencer is clocked every 2048 cycles (512Hz)", func(t *testing.T) {
+		var timer Timer
+		var clocks []int
+		for i := range 4096 {
+			timer = timer.Step()
+			if timer.Fell(FrameSequencerBit) {
+				clocks = append(clocks, i)
+			}
+		}
+		assert.Equal(t, []int{2047, 4095}, clocks)
+		assert.True(t, timer.Fell(0), "bit 0 falls too")
+		assert.False(t, timer.CounterBit(FrameSequencerBit))
+	})
+	t.Run("write to DIV clocks the frame sequencer", func(t *testing.T) {
+		var timer Timer
+		timer.counter = 1 << FrameSequencerBit
+		assert.True(t, timer.CounterBit(FrameSequencerBit))
+
+		timer = timer.Step()
+		assert.False(t, timer.Fell(FrameSequencerBit))
+		timer = timer.Write(DIV, 0).Step()
+		assert.True(t, timer.Fell(FrameSequencerBit))
+		assert.False(t, timer.Fell(SerialClockBit), "bit wasn't set")
+	})
+	t.Run("counter wraps at 14 bits", func(t *testing.T) {
+		var timer Timer
+		timer.counter = 0x3FFF
+		timer = timer.Step()
+		assert.Zero(t, timer.Read(DIV))
+		assert.True(t, timer.Fell(13))
+	})
+}
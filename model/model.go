@@ -0,0 +1,46 @@
+// Package model identifies the Game Boy hardware models, which differ in
+// their state after the boot rom has run.
+package model
+
+//go:generate stringer -type Model -linecomment -output model_string.go
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Model is a Game Boy hardware model.
+type Model int
+
+const (
+	DMG0 Model = iota // DMG0
+	DMG               // DMG
+	MGB               // MGB
+	SGB               // SGB
+	SGB2              // SGB2
+	CGB               // CGB
+	AGB               // AGB
+)
+
+// Models lists every model.
+var Models = []Model{DMG0, DMG, MGB, SGB, SGB2, CGB, AGB}
+
+// Parse returns the model named by s, such as "dmg" or "CGB".
+func Parse(s string) (Model, error) {
+	for _, m := range Models {
+		if strings.EqualFold(s, m.String()) {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown model %q", s)
+}
+
+// Color reports whether the model has the CGB hardware.
+func (m Model) Color() bool {
+	return m == CGB || m == AGB
+}
+
+// Super reports whether the model is a Super Game Boy.
+func (m Model) Super() bool {
+	return m == SGB || m == SGB2
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	for _, m := range Models {
+		got, err := Parse(m.String())
+		assert.NoError(t, err)
+		assert.Equal(t, m, got)
+	}
+
+	m, err := Parse("cgb")
+	assert.NoError(t, err)
+	assert.Equal(t, CGB, m)
+
+	_, err = Parse("gbc")
+	assert.EqualError(t, err, `unknown model "gbc"`)
+}
@@ -0,0 +1,196 @@
+// Package vcd writes the signals of the CPU & timer as a Value Change Dump,
+// for viewing in a waveform viewer such as GTKWave.
+package vcd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wmarshpersonal/gogeebee/cpu"
+	"github.com/wmarshpersonal/gogeebee/gb"
+)
+
+// mcycleNs is the length of an M-cycle (at 1.048576MHz) in nanoseconds, rounded.
+const mcycleNs = 954
+
+// Trigger selects a cycle to start or stop capturing at.
+type Trigger func(rec cpu.CycleRecord, s cpu.State) bool
+
+// AtCycle triggers on the nth cycle run by the core.
+func AtCycle(n int) Trigger {
+	return func(rec cpu.CycleRecord, _ cpu.State) bool { return rec.N == n }
+}
+
+// AtPC triggers on the cycle that fetches the instruction at pc.
+func AtPC(pc uint16) Trigger {
+	return func(rec cpu.CycleRecord, _ cpu.State) bool { return rec.Cycle.Fetch && rec.Addr == pc }
+}
+
+// OnAccess triggers on a cycle that reads or writes addr.
+func OnAccess(addr uint16) Trigger {
+	return func(rec cpu.CycleRecord, _ cpu.State) bool { return (rec.Read || rec.Write) && rec.Addr == addr }
+}
+
+// signal is a wire in the dump.
+type signal struct {
+	scope, name string
+	width       int
+}
+
+var (
+	cpuSignals = []signal{
+		{"cpu", "addr", 16},
+		{"cpu", "data", 8},
+		{"cpu", "rd", 1},
+		{"cpu", "wr", 1},
+		{"cpu", "ime", 1},
+		{"cpu", "ie", 8},
+		{"cpu", "if", 5},
+		{"cpu", "irq", 1}, // an enabled interrupt is requested
+	}
+	timerSignals = []signal{
+		{"timer", "tima", 8},
+		{"timer", "reload", 1}, // the TIMA reload delay line
+		{"timer", "ir", 1},
+	}
+)
+
+// Writer writes a Value Change Dump, sampling the signals once per M-cycle.
+// It is a cpu.CycleObserver, so it is attached to a core as its CycleObserver.
+type Writer struct {
+	// Start & Stop select the window captured: from the cycle Start triggers
+	// on, or the first cycle if nil, up to & including the cycle Stop triggers
+	// on, or for as long as the core runs if nil.
+	Start, Stop Trigger
+
+	// Timer, if set, is sampled along with the CPU. It must be stepped by the
+	// bus during the cycle.
+	Timer *gb.Timer
+
+	w                 io.Writer
+	signals           []signal
+	last              []string
+	started, finished bool
+}
+
+// NewWriter returns a writer dumping to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// ObserveCycle samples the signals of the cycle, writing those that changed.
+func (w *Writer) ObserveCycle(rec cpu.CycleRecord, s cpu.State) error {
+	if w.finished {
+		return nil
+	}
+	if !w.started {
+		if w.Start != nil && !w.Start(rec, s) {
+			return nil
+		}
+		w.started = true
+		if err := w.header(); err != nil {
+			return err
+		}
+	}
+	if w.Stop != nil && w.Stop(rec, s) {
+		w.finished = true
+	}
+
+	values := w.sample(rec, s)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "#%d\n", rec.N*mcycleNs)
+	if w.last == nil {
+		sb.WriteString("$dumpvars\n")
+	}
+	for i, v := range values {
+		if w.last == nil || w.last[i] != v {
+			if w.signals[i].width == 1 {
+				fmt.Fprintf(&sb, "%s%s\n", v, id(i))
+			} else {
+				fmt.Fprintf(&sb, "b%s %s\n", v, id(i))
+			}
+		}
+	}
+	if w.last == nil {
+		sb.WriteString("$end\n")
+	}
+	w.last = values
+
+	_, err := io.WriteString(w.w, sb.String())
+	return err
+}
+
+// sample returns the value of each signal, as binary.
+func (w *Writer) sample(rec cpu.CycleRecord, s cpu.State) []string {
+	data := "z" // the data bus floats between accesses
+	if rec.Read || rec.Write {
+		data = bin(uint(rec.Data), 8)
+	}
+	values := []string{
+		bin(uint(rec.Addr), 16),
+		data,
+		bin(bit(rec.Read), 1),
+		bin(bit(rec.Write), 1),
+		bin(bit(s.IME), 1),
+		bin(uint(s.IE), 8),
+		bin(uint(s.IF&0x1F), 5),
+		bin(bit(s.Pending() != 0), 1),
+	}
+	if w.Timer != nil {
+		values = append(values,
+			bin(uint(w.Timer.Read(gb.TIMA)), 8),
+			bin(bit(w.Timer.ReloadPending()), 1),
+			bin(bit(w.Timer.IR), 1),
+		)
+	}
+	return values
+}
+
+// header writes the definitions of the signals.
+func (w *Writer) header() error {
+	w.signals = cpuSignals
+	if w.Timer != nil {
+		w.signals = append(w.signals[:len(w.signals):len(w.signals)], timerSignals...)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("$version gogeebee $end\n")
+	sb.WriteString("$timescale 1ns $end\n")
+	sb.WriteString("$scope module gb $end\n")
+	scope := ""
+	for i, sig := range w.signals {
+		if sig.scope != scope {
+			if scope != "" {
+				sb.WriteString("$upscope $end\n")
+			}
+			scope = sig.scope
+			fmt.Fprintf(&sb, "$scope module %s $end\n", scope)
+		}
+		if sig.width == 1 {
+			fmt.Fprintf(&sb, "$var wire 1 %s %s $end\n", id(i), sig.name)
+		} else {
+			fmt.Fprintf(&sb, "$var wire %d %s %s [%d:0] $end\n", sig.width, id(i), sig.name, sig.width-1)
+		}
+	}
+	sb.WriteString("$upscope $end\n$upscope $end\n$enddefinitions $end\n")
+
+	_, err := io.WriteString(w.w, sb.String())
+	return err
+}
+
+// id returns the identifier code of the ith signal.
+func id(i int) string {
+	return string(rune('!' + i))
+}
+
+func bin(v uint, width int) string {
+	return fmt.Sprintf("%0*b", width, v)
+}
+
+func bit(b bool) uint {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,187 @@
+package vcd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wmarshpersonal/gogeebee/cpu"
+	"github.com/wmarshpersonal/gogeebee/gb"
+)
+
+// timerBus is flat memory with a timer stepped every cycle.
+type timerBus struct {
+	mem   [0x10000]uint8
+	timer gb.Timer
+}
+
+func (b *timerBus) Read(addr uint16) uint8 {
+	b.timer = b.timer.Step()
+	return b.mem[addr]
+}
+
+func (b *timerBus) Write(addr uint16, v uint8) {
+	b.timer = b.timer.Step()
+	b.mem[addr] = v
+}
+
+func (b *timerBus) Tick() {
+	b.timer = b.timer.Step()
+}
+
+func (b *timerBus) Interrupts() uint8 {
+	if b.timer.IR {
+		return cpu.IntTimer
+	}
+	return 0
+}
+
+// newTimerCore returns a core running program, with the timer about to overflow.
+func newTimerCore(program ...uint8) (*cpu.Core, *timerBus) {
+	bus := &timerBus{}
+	copy(bus.mem[0x100:], program)
+	bus.timer = bus.timer.Write(gb.TAC, 0b101).Step().Write(gb.TIMA, 0xFF).Step()
+
+	c := cpu.NewCore(bus)
+	c.IR, c.S, c.PC = bus.mem[0x100], 0, 0x0101
+	c.IE, c.IF = cpu.IntTimer, 0
+	return c, bus
+}
+
+// dumpTimes returns the timestamps of a dump.
+func dumpTimes(dump string) []string {
+	var times []string
+	for _, line := range strings.Split(dump, "\n") {
+		if strings.HasPrefix(line, "#") {
+			times = append(times, line)
+		}
+	}
+	return times
+}
+
+func TestWriter(t *testing.T) {
+	t.Run("dumps every cycle", func(t *testing.T) {
+		var sb strings.Builder
+		c, bus := newTimerCore(
+			0xE0, 0x80, // ldh ($80), a
+			0x00, // nop
+		)
+		w := NewWriter(&sb)
+		w.Timer = &bus.timer
+		c.CycleObserver = w
+
+		_, err := c.RunCycles(4)
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Join([]string{
+			"$version gogeebee $end",
+			"$timescale 1ns $end",
+			"$scope module gb $end",
+			"$scope module cpu $end",
+			"$var wire 16 ! addr [15:0] $end",
+			`$var wire 8 " data [7:0] $end`,
+			"$var wire 1 # rd $end",
+			"$var wire 1 $ wr $end",
+			"$var wire 1 % ime $end",
+			"$var wire 8 & ie [7:0] $end",
+			"$var wire 5 ' if [4:0] $end",
+			"$var wire 1 ( irq $end",
+			"$upscope $end",
+			"$scope module timer $end",
+			"$var wire 8 ) tima [7:0] $end",
+			"$var wire 1 * reload $end",
+			"$var wire 1 + ir $end",
+			"$upscope $end",
+			"$upscope $end",
+			"$enddefinitions $end",
+			// ldh: read n
+			"#0",
+			"$dumpvars",
+			"b0000000100000001 !",
+			`b10000000 "`,
+			"1#",
+			"0$",
+			"0%",
+			"b00000100 &",
+			"b00000 '",
+			"0(",
+			"b11111111 )",
+			"0*",
+			"0+",
+			"$end",
+			// ldh: write A; TIMA overflows
+			"#954",
+			"b1111111110000000 !",
+			`b00000001 "`,
+			"0#",
+			"1$",
+			"b00000000 )",
+			"1*",
+			// fetch; TIMA is reloaded and the interrupt requested
+			"#1908",
+			"b0000000100000010 !",
+			`b00000000 "`,
+			"1#",
+			"0$",
+			"b00100 '",
+			"1(",
+			"0*",
+			"1+",
+			// nop
+			"#2862",
+			"b0000000100000011 !",
+			"0+",
+			"",
+		}, "\n"), sb.String())
+	})
+
+	t.Run("reset state", func(t *testing.T) {
+		var sb strings.Builder
+		c := cpu.NewCore(&timerBus{})
+		c.CycleObserver = NewWriter(&sb)
+
+		_, err := c.RunCycles(1)
+		assert.NoError(t, err)
+		assert.Contains(t, sb.String(), "b00001 '\n", "IF's unused bits are left out")
+		for _, line := range strings.Split(sb.String(), "\n") {
+			if v, id, ok := strings.Cut(line, " "); ok && strings.HasPrefix(line, "b") {
+				width := map[string]int{"!": 16, `"`: 8, "&": 8, "'": 5}[id]
+				assert.Lenf(t, v, 1+width, "%s is %d bits wide", id, width)
+			}
+		}
+	})
+
+	t.Run("triggers", func(t *testing.T) {
+		var sb strings.Builder
+		c, _ := newTimerCore(0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00)
+		w := NewWriter(&sb)
+		w.Start, w.Stop = AtPC(0x0102), OnAccess(0x0104)
+		c.CycleObserver = w
+
+		_, err := c.RunCycles(8)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"#954", "#1908", "#2862"}, dumpTimes(sb.String()))
+		assert.NotContains(t, sb.String(), "timer")
+
+		// the HALT bug fetches inc a without advancing PC past it
+		sb.Reset()
+		c, _ = newTimerCore(
+			0x76, // halt
+			0x3C, // inc a
+		)
+		c.IF = cpu.IntTimer
+		w = NewWriter(&sb)
+		w.Start, w.Stop = AtPC(0x0101), AtCycle(1)
+		c.CycleObserver = w
+		_, err = c.RunCycles(3)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"#0", "#954"}, dumpTimes(sb.String()), "starts on halt's fetch of inc a")
+
+		sb.Reset()
+		w = NewWriter(&sb)
+		w.Start = AtCycle(100)
+		c.CycleObserver = w
+		_, err = c.RunCycles(8)
+		assert.NoError(t, err)
+		assert.Empty(t, sb.String())
+	})
+}